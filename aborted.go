@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"time"
+
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 )
@@ -29,11 +31,14 @@ import (
 //
 // RPC Mapping: ABORTED
 type AbortedError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code       int    `json:"errorCode"`
+	Message    string `json:"errorMessage"`
+	cause      error
+	stack      stack
+	reason     string
+	metadata   map[string]string
+	retryDelay time.Duration
+	rpcCode    codes.Code
 }
 
 // NewAbortedError returns a new AbortedError.
@@ -42,13 +47,15 @@ func NewAbortedError(Message string, cause ...error) *AbortedError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &AbortedError{
+	e := &AbortedError{
 		Code:    409,
 		Message: "ABORTED. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.Aborted,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -58,6 +65,9 @@ func (e *AbortedError) Error() string { return errorStr(e) }
 func (e *AbortedError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *AbortedError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -72,7 +82,27 @@ func (e *AbortedError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *AbortedError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *AbortedError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *AbortedError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *AbortedError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *AbortedError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
+// GetRetryDelay returns how long the client should wait before retrying, or
+// zero if no delay was set.
+func (e *AbortedError) GetRetryDelay() time.Duration { return e.retryDelay }
+
+// SetRetryDelay sets how long the client should wait before retrying. The
+// delay is sent to gRPC clients in a RetryInfo detail.
+func (e *AbortedError) SetRetryDelay(d time.Duration) { e.retryDelay = d }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *AbortedError) GRPCStatus() *status.Status {
-	return status.New(e.rpcCode, e.Message)
+	return withRetryDelay(status.New(e.rpcCode, e.Message), e.retryDelay)
 }
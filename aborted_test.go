@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	assert "github.com/stretchr/testify/assert"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
 	codes "google.golang.org/grpc/codes"
 )
 
@@ -114,6 +116,40 @@ func TestAbortedErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestAbortedErrorGetReason(t *testing.T) {
+	for _, test := range AbortedErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewAbortedError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestAbortedErrorGetMetadata(t *testing.T) {
+	for _, test := range AbortedErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewAbortedError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
+func TestAbortedErrorRetryDelay(t *testing.T) {
+	for _, test := range AbortedErrorTests {
+		assert.Equal(t, time.Duration(0), test.err.GetRetryDelay())
+		assert.Equal(t, 0, len(test.err.GRPCStatus().Details()))
+	}
+
+	err := NewAbortedError("Message")
+	err.SetRetryDelay(5 * time.Second)
+	assert.Equal(t, 5*time.Second, err.GetRetryDelay())
+
+	details := err.GRPCStatus().Details()
+	if assert.Equal(t, 1, len(details)) {
+		assert.Equal(t, 5*time.Second, details[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+	}
+}
+
 func TestAbortedErrorJson(t *testing.T) {
 	for _, test := range AbortedErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"sort"
+
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// AggregationPolicy selects the gRPC code that represents an Errors holding
+// more than one error. It is passed the code of every error in the Errors, in
+// order, followed by the codes of any omitted errors (see Errors.Omitted), and
+// is never passed an empty slice.
+type AggregationPolicy func(cs []codes.Code) codes.Code
+
+// DefaultCodeRanking orders gRPC codes from most to least severe. Server
+// errors outrank client errors, and among server errors those the client can
+// act upon (UNAVAILABLE, DEADLINE_EXCEEDED) outrank those it can't.
+var DefaultCodeRanking = []codes.Code{
+	codes.Unavailable,
+	codes.DeadlineExceeded,
+	codes.DataLoss,
+	codes.Internal,
+	codes.Unimplemented,
+	codes.Unknown,
+	codes.Unauthenticated,
+	codes.PermissionDenied,
+	codes.ResourceExhausted,
+	codes.FailedPrecondition,
+	codes.Aborted,
+	codes.AlreadyExists,
+	codes.NotFound,
+	codes.OutOfRange,
+	codes.InvalidArgument,
+	codes.Canceled,
+}
+
+// aggregation stores the global aggregation policy used by Errors.
+var aggregation = RankedAggregation(DefaultCodeRanking...)
+
+// SetAggregationPolicy changes the global policy used by Errors to choose its
+// gRPC and HTTP codes when it holds more than one error. A nil policy restores
+// the default, RankedAggregation(DefaultCodeRanking...).
+func SetAggregationPolicy(p AggregationPolicy) {
+	if p == nil {
+		p = RankedAggregation(DefaultCodeRanking...)
+	}
+	aggregation = p
+}
+
+// RankedAggregation returns an AggregationPolicy that uses the code shared by
+// all errors if there is one, and otherwise the code appearing first in
+// ranking. Codes missing from ranking are outranked by every code in it; if no
+// code is ranked, UNKNOWN is used.
+func RankedAggregation(ranking ...codes.Code) AggregationPolicy {
+	rank := make(map[codes.Code]int, len(ranking))
+	for i, c := range ranking {
+		if _, ok := rank[c]; !ok {
+			rank[c] = i
+		}
+	}
+	return func(cs []codes.Code) codes.Code {
+		best, bestRank := codes.Unknown, len(ranking)
+		shared := true
+		for _, c := range cs {
+			if c != cs[0] {
+				shared = false
+			}
+			if r, ok := rank[c]; ok && r < bestRank {
+				best, bestRank = c, r
+			}
+		}
+		if shared {
+			return cs[0]
+		}
+		return best
+	}
+}
+
+// aggregate returns the gRPC code selected by the global aggregation policy for
+// errs and the errors omitted from them, counted by code in omitted, along
+// with the first error in errs having that code, if any. Nil errors are
+// ignored.
+func aggregate(errs []error, omitted map[codes.Code]int) (codes.Code, error) {
+	var (
+		cs      []codes.Code
+		members []error
+	)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		cs = append(cs, status.Code(err))
+		members = append(members, err)
+	}
+	omittedCodes := make([]codes.Code, 0, len(omitted))
+	for c := range omitted {
+		omittedCodes = append(omittedCodes, c)
+	}
+	sort.Slice(omittedCodes, func(i, j int) bool { return omittedCodes[i] < omittedCodes[j] })
+	for _, c := range omittedCodes {
+		for n := 0; n < omitted[c]; n++ {
+			cs = append(cs, c)
+		}
+	}
+	if len(cs) == 0 {
+		return codes.Unknown, nil
+	}
+	code := aggregation(cs)
+	for i, c := range cs[:len(members)] {
+		if c == code {
+			return code, members[i]
+		}
+	}
+	return code, nil
+}
+
+// aggregateStatus returns a status with msg and the code selected by the global
+// aggregation policy for errs and the errors omitted from them, carrying the
+// status of each error in errs as a detail entry.
+func aggregateStatus(errs []error, omitted map[codes.Code]int, msg string) *status.Status {
+	code, _ := aggregate(errs, omitted)
+	s := status.New(code, msg)
+	if code == codes.OK {
+		return s
+	}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if ds, dErr := s.WithDetails(status.Convert(err).Proto()); dErr == nil {
+			s = ds
+		}
+	}
+	return s
+}
+
+// httpCode maps a gRPC code to the correlating HTTP status code.
+func httpCode(c codes.Code) int {
+	switch c {
+	case codes.Aborted:
+		return 409
+	case codes.AlreadyExists:
+		return 409
+	case codes.Canceled:
+		return 499
+	case codes.DataLoss:
+		return 500
+	case codes.DeadlineExceeded:
+		return 504
+	case codes.FailedPrecondition:
+		return 400
+	case codes.Internal:
+		return 500
+	case codes.InvalidArgument:
+		return 400
+	case codes.NotFound:
+		return 404
+	case codes.OutOfRange:
+		return 400
+	case codes.PermissionDenied:
+		return 403
+	case codes.ResourceExhausted:
+		return 429
+	case codes.Unauthenticated:
+		return 401
+	case codes.Unavailable:
+		return 503
+	case codes.Unimplemented:
+		return 501
+	case codes.Unknown:
+		return 500
+	}
+	return 500
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+func TestRankedAggregation(t *testing.T) {
+	tests := []struct {
+		policy AggregationPolicy
+		cs     []codes.Code
+		code   codes.Code
+	}{
+		{
+			RankedAggregation(DefaultCodeRanking...),
+			[]codes.Code{codes.NotFound, codes.NotFound},
+			codes.NotFound,
+		},
+		{
+			RankedAggregation(DefaultCodeRanking...),
+			[]codes.Code{codes.NotFound, codes.Internal, codes.Unavailable},
+			codes.Unavailable,
+		},
+		{
+			RankedAggregation(DefaultCodeRanking...),
+			[]codes.Code{codes.InvalidArgument, codes.PermissionDenied},
+			codes.PermissionDenied,
+		},
+		{
+			RankedAggregation(codes.NotFound),
+			[]codes.Code{codes.Internal, codes.NotFound},
+			codes.NotFound,
+		},
+		{
+			RankedAggregation(),
+			[]codes.Code{codes.Internal, codes.NotFound},
+			codes.Unknown,
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, test.policy(test.cs))
+	}
+}
+
+func TestSetAggregationPolicy(t *testing.T) {
+	defer SetAggregationPolicy(nil)
+
+	errs := NewErrors(NewNotFoundError("foo"), NewUnavailableError("bar"))
+	assert.Equal(t, 503, errs.GetCode())
+
+	SetAggregationPolicy(RankedAggregation(codes.NotFound))
+	assert.Equal(t, 404, errs.GetCode())
+	assert.Equal(t, codes.NotFound, errs.GRPCStatus().Code())
+
+	SetAggregationPolicy(nil)
+	assert.Equal(t, 503, errs.GetCode())
+}
+
+func TestErrorsAggregation(t *testing.T) {
+	tests := []struct {
+		errs      *Errors
+		code      int
+		rpcCode   codes.Code
+		timeout   bool
+		temporary bool
+	}{
+		{
+			NewErrors(NewNotFoundError("foo"), NewNotFoundError("bar")),
+			404,
+			codes.NotFound,
+			false,
+			false,
+		},
+		{
+			NewErrors(NewNotFoundError("foo"), NewInternalError("bar"), NewUnavailableError("baz")),
+			503,
+			codes.Unavailable,
+			false,
+			false,
+		},
+		{
+			NewErrors(NewInvalidArgumentError("foo"), status.Error(codes.AlreadyExists, "bar")),
+			409,
+			codes.AlreadyExists,
+			false,
+			false,
+		},
+		{
+			NewErrors(NewUnavailableError("foo"), NewUnknownError("bar")),
+			503,
+			codes.Unavailable,
+			false,
+			true,
+		},
+		{
+			NewErrors(NewDeadlineExceededError("foo"), nil, NewCanceledError("bar")),
+			504,
+			codes.DeadlineExceeded,
+			true,
+			false,
+		},
+		{
+			NewErrors(NewDeadlineExceededError("foo"), errors.New("bar")),
+			504,
+			codes.DeadlineExceeded,
+			false,
+			false,
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, test.errs.GetCode())
+		assert.Equal(t, test.rpcCode, test.errs.GRPCStatus().Code())
+		assert.Equal(t, test.timeout, test.errs.Timeout())
+		assert.Equal(t, test.temporary, test.errs.Temporary())
+	}
+}
+
+func TestErrorsGRPCStatusDetails(t *testing.T) {
+	errs := NewErrors(NewNotFoundError("foo"), errors.New("bar"))
+	details := errs.GRPCStatus().Details()
+	if assert.Equal(t, 2, len(details)) {
+		s := status.FromProto(details[0].(*spb.Status))
+		assert.Equal(t, codes.NotFound, s.Code())
+		assert.Equal(t, "NOT FOUND. foo", s.Message())
+		s = status.FromProto(details[1].(*spb.Status))
+		assert.Equal(t, codes.Unknown, s.Code())
+		assert.Equal(t, "bar", s.Message())
+	}
+}
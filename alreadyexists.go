@@ -16,11 +16,13 @@ import (
 //
 // RPC Mapping: ALREADY_EXISTS
 type AlreadyExistsError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code     int    `json:"errorCode"`
+	Message  string `json:"errorMessage"`
+	cause    error
+	stack    stack
+	reason   string
+	metadata map[string]string
+	rpcCode  codes.Code
 }
 
 // NewAlreadyExistsError returns a new AlreadyExistsError.
@@ -29,13 +31,15 @@ func NewAlreadyExistsError(Message string, cause ...error) *AlreadyExistsError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &AlreadyExistsError{
+	e := &AlreadyExistsError{
 		Code:    409,
 		Message: "ALREADY EXISTS. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.AlreadyExists,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -45,6 +49,9 @@ func (e *AlreadyExistsError) Error() string { return errorStr(e) }
 func (e *AlreadyExistsError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *AlreadyExistsError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -59,6 +66,18 @@ func (e *AlreadyExistsError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *AlreadyExistsError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *AlreadyExistsError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *AlreadyExistsError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *AlreadyExistsError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *AlreadyExistsError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *AlreadyExistsError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
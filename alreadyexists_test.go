@@ -113,6 +113,24 @@ func TestAlreadyExistsErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestAlreadyExistsErrorGetReason(t *testing.T) {
+	for _, test := range AlreadyExistsErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewAlreadyExistsError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestAlreadyExistsErrorGetMetadata(t *testing.T) {
+	for _, test := range AlreadyExistsErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewAlreadyExistsError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestAlreadyExistsErrorJson(t *testing.T) {
 	for _, test := range AlreadyExistsErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -0,0 +1,293 @@
+// Package breaker implements a circuit breaker that classifies the outcome of
+// calls to a dependency by the kinds of github.com/weathersource/go-errors.
+//
+// Only server-side failures, such as UNAVAILABLE, DEADLINE_EXCEEDED and
+// INTERNAL errors, count against the dependency. Client errors, such as
+// INVALID_ARGUMENT or NOT_FOUND, show the dependency is healthy and count as
+// successes.
+//
+//	b := breaker.New(breaker.Settings{Name: "stations"})
+//	err := b.Do(ctx, func(ctx context.Context) error {
+//		return client.GetStation(ctx, id)
+//	})
+package breaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	errors "github.com/weathersource/go-errors"
+	codes "google.golang.org/grpc/codes"
+)
+
+// State is the state of a Breaker.
+type State int
+
+// Breaker states
+const (
+	// Closed lets calls through, counting their failures.
+	Closed State = iota
+
+	// Open rejects calls until its timeout has passed.
+	Open
+
+	// HalfOpen lets a limited number of probe calls through to decide whether
+	// to close or reopen.
+	HalfOpen
+)
+
+// String returns the name of s.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "CLOSED"
+	case Open:
+		return "OPEN"
+	case HalfOpen:
+		return "HALF_OPEN"
+	}
+	return "UNKNOWN"
+}
+
+// Clock tells the time for a Breaker. It may be replaced in Settings to
+// control the passage of time in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock used when Settings do not set one.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Settings configure a Breaker. Zero fields take the documented defaults.
+type Settings struct {
+	// Name identifies the breaker in the errors it returns.
+	Name string
+
+	// Window is the period over which calls are counted while closed. The
+	// counts are reset at the end of each window; calls ending in a later
+	// window than they started in are counted in the window they end in.
+	// Defaults to 10 seconds.
+	Window time.Duration
+
+	// MinRequests is the number of calls in a window needed before the breaker
+	// may open. Defaults to 10.
+	MinRequests int
+
+	// FailureRatio is the ratio of failed calls in a window, from 0 to 1, at
+	// which the breaker opens. Defaults to 0.5.
+	FailureRatio float64
+
+	// OpenTimeout is how long the breaker stays open before letting probe
+	// calls through. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of probe calls let through while half
+	// open. The breaker closes once they all succeed, and reopens on the first
+	// failure. Defaults to 1.
+	HalfOpenRequests int
+
+	// IsFailure reports whether a call that returned a non-nil err failed.
+	// Defaults to IsFailure.
+	IsFailure func(err error) bool
+
+	// Clock is the clock used to measure windows and timeouts. Defaults to the
+	// system clock.
+	Clock Clock
+}
+
+// IsFailure reports whether err is a server-side failure counting against a
+// dependency: an error of kind UNAVAILABLE, DEADLINE_EXCEEDED, INTERNAL,
+// UNKNOWN or DATA_LOSS, as classified by errors.Kind. Other errors are
+// client errors, and count as successes.
+func IsFailure(err error) bool {
+	switch errors.Kind(err) {
+	case codes.Unavailable,
+		codes.DeadlineExceeded,
+		codes.Internal,
+		codes.Unknown,
+		codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use by multiple
+// goroutines.
+type Breaker struct {
+	mu         sync.Mutex
+	settings   Settings
+	state      State
+	generation uint64
+	window     uint64
+	requests   int
+	successes  int
+	failures   int
+	expiry     time.Time
+}
+
+// New returns a closed Breaker configured by s.
+func New(s Settings) *Breaker {
+	if s.Window <= 0 {
+		s.Window = 10 * time.Second
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = 10
+	}
+	if s.FailureRatio <= 0 {
+		s.FailureRatio = 0.5
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = 30 * time.Second
+	}
+	if s.HalfOpenRequests <= 0 {
+		s.HalfOpenRequests = 1
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = IsFailure
+	}
+	if s.Clock == nil {
+		s.Clock = systemClock{}
+	}
+	b := &Breaker{settings: s}
+	b.setState(Closed, s.Clock.Now())
+	return b
+}
+
+// State returns the current state of b.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.update(b.settings.Clock.Now())
+	return b.state
+}
+
+// Do calls fn if b lets the call through, and records its outcome. Calls that
+// were canceled are not recorded. If b rejects the call, Do returns an
+// *errors.UnavailableError whose retry delay is the time until b lets calls
+// through again; otherwise it returns the error of fn.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	t, err := b.before()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			b.after(t, errors.NewInternalError(fmt.Sprintf("panic: %v", r)))
+			panic(r)
+		}
+	}()
+	err = fn(ctx)
+	b.after(t, err)
+	return err
+}
+
+// ticket identifies the generation and window in which a call started.
+type ticket struct {
+	generation uint64
+	window     uint64
+}
+
+// before checks whether b lets a call through, returning the ticket of the
+// call.
+func (b *Breaker) before() (ticket, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.settings.Clock.Now()
+	b.update(now)
+
+	switch {
+	case b.state == Open:
+		return ticket{}, b.rejection(b.expiry.Sub(now))
+	case b.state == HalfOpen && b.requests >= b.settings.HalfOpenRequests:
+		return ticket{}, b.rejection(0)
+	}
+	b.requests++
+	return ticket{generation: b.generation, window: b.window}, nil
+}
+
+// after records the outcome of the call with ticket t. Outcomes of calls from
+// an earlier generation, started before b last changed state, are ignored.
+// Calls that started in an earlier window of the same generation are counted
+// in the current window.
+func (b *Breaker) after(t ticket, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.settings.Clock.Now()
+	b.update(now)
+	if t.generation != b.generation {
+		return
+	}
+	current := t.window == b.window
+
+	switch {
+	case err != nil && errors.Kind(err) == codes.Canceled:
+		if current {
+			b.requests--
+		}
+		return
+	case !current:
+		b.requests++
+	}
+
+	switch {
+	case err != nil && b.settings.IsFailure(err):
+		b.failures++
+		if b.state == HalfOpen || (b.requests >= b.settings.MinRequests &&
+			float64(b.failures)/float64(b.requests) >= b.settings.FailureRatio) {
+			b.setState(Open, now)
+		}
+	default:
+		b.successes++
+		if b.state == HalfOpen && b.successes >= b.settings.HalfOpenRequests {
+			b.setState(Closed, now)
+		}
+	}
+}
+
+// update moves b on to its next window or state if the current one has
+// expired.
+func (b *Breaker) update(now time.Time) {
+	if now.Before(b.expiry) {
+		return
+	}
+	switch b.state {
+	case Closed:
+		b.window++
+		b.requests, b.successes, b.failures = 0, 0, 0
+		b.expiry = now.Add(b.settings.Window)
+	case Open:
+		b.setState(HalfOpen, now)
+	}
+}
+
+// setState moves b to state, starting a new generation.
+func (b *Breaker) setState(state State, now time.Time) {
+	b.state = state
+	b.generation++
+	b.window++
+	b.requests, b.successes, b.failures = 0, 0, 0
+	switch state {
+	case Closed:
+		b.expiry = now.Add(b.settings.Window)
+	case Open:
+		b.expiry = now.Add(b.settings.OpenTimeout)
+	default:
+		b.expiry = time.Time{}
+	}
+}
+
+// rejection returns the error for a call rejected by b, which may be retried
+// after delay.
+func (b *Breaker) rejection(delay time.Duration) error {
+	msg := "circuit breaker is open"
+	if b.settings.Name != "" {
+		msg = fmt.Sprintf("circuit breaker %q is open", b.settings.Name)
+	}
+	err := errors.NewUnavailableError(msg)
+	err.SetRetryDelay(delay)
+	return err
+}
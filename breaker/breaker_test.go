@@ -0,0 +1,261 @@
+package breaker
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	errors "github.com/weathersource/go-errors"
+)
+
+// fakeClock is a Clock whose time advances only when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker() (*Breaker, *fakeClock) {
+	clock := &fakeClock{now: time.Now()}
+	return New(Settings{
+		Name:         "stations",
+		Window:       time.Minute,
+		MinRequests:  4,
+		FailureRatio: 0.5,
+		OpenTimeout:  10 * time.Second,
+		Clock:        clock,
+	}), clock
+}
+
+// returning returns an operation that returns err.
+func returning(err error) func(context.Context) error {
+	return func(context.Context) error { return err }
+}
+
+func TestStateString(t *testing.T) {
+	assert.Equal(t, "CLOSED", Closed.String())
+	assert.Equal(t, "OPEN", Open.String())
+	assert.Equal(t, "HALF_OPEN", HalfOpen.String())
+	assert.Equal(t, "UNKNOWN", State(-1).String())
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		err     error
+		failure bool
+	}{
+		{errors.NewUnavailableError("foo"), true},
+		{errors.NewDeadlineExceededError("foo"), true},
+		{errors.NewInternalError("foo"), true},
+		{errors.NewUnknownError("foo"), true},
+		{errors.NewDataLossError("foo"), true},
+		{context.DeadlineExceeded, true},
+		{stderrors.New("foo"), true},
+		{errors.NewInvalidArgumentError("foo"), false},
+		{errors.NewNotFoundError("foo"), false},
+		{errors.NewPermissionDeniedError("foo"), false},
+		{errors.NewResourceExhaustedError("foo"), false},
+		{errors.NewCanceledError("foo"), false},
+		{fmt.Errorf("stations: %w", errors.NewNotFoundError("foo")), false},
+		{fmt.Errorf("stations: %w", errors.NewInvalidArgumentError("foo")), false},
+		{fmt.Errorf("stations: %w", context.Canceled), false},
+		{fmt.Errorf("stations: %w", errors.NewUnavailableError("foo")), true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.failure, IsFailure(test.err), test.err.Error())
+	}
+}
+
+func TestBreakerOpens(t *testing.T) {
+	b, clock := newTestBreaker()
+	ctx := context.Background()
+	unavailable := errors.NewUnavailableError("foo")
+
+	// too few requests to open
+	assert.Equal(t, unavailable, b.Do(ctx, returning(unavailable)))
+	assert.Equal(t, unavailable, b.Do(ctx, returning(unavailable)))
+	assert.Nil(t, b.Do(ctx, returning(nil)))
+	assert.Equal(t, Closed, b.State())
+
+	assert.Equal(t, unavailable, b.Do(ctx, returning(unavailable)))
+	assert.Equal(t, Open, b.State())
+
+	clock.Advance(4 * time.Second)
+	calls := 0
+	err := b.Do(ctx, func(context.Context) error {
+		calls++
+		return nil
+	})
+	assert.Equal(t, 0, calls)
+	rejected, ok := err.(*errors.UnavailableError)
+	if assert.True(t, ok) {
+		assert.Equal(t, "UNAVAILABLE. Unable to handle the request due to a temporary overloading or maintenance. circuit breaker \"stations\" is open", rejected.GetMessage())
+		assert.Equal(t, 6*time.Second, rejected.GetRetryDelay())
+	}
+}
+
+func TestBreakerClientErrors(t *testing.T) {
+	b, _ := newTestBreaker()
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		b.Do(ctx, returning(errors.NewNotFoundError("foo")))
+		b.Do(ctx, returning(errors.NewInvalidArgumentError("foo")))
+	}
+	assert.Equal(t, Closed, b.State())
+
+	// wrapped client errors count as successes
+	for i := 0; i < 10; i++ {
+		b.Do(ctx, returning(fmt.Errorf("stations: %w", errors.NewNotFoundError("foo"))))
+	}
+	assert.Equal(t, Closed, b.State())
+
+	// canceled calls are not counted
+	for i := 0; i < 3; i++ {
+		b.Do(ctx, returning(errors.NewInternalError("foo")))
+		b.Do(ctx, returning(context.Canceled))
+		b.Do(ctx, returning(fmt.Errorf("stations: %w", context.Canceled)))
+	}
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreakerWindow(t *testing.T) {
+	b, clock := newTestBreaker()
+	ctx := context.Background()
+	internal := errors.NewInternalError("foo")
+
+	for i := 0; i < 3; i++ {
+		b.Do(ctx, returning(internal))
+	}
+	clock.Advance(time.Minute)
+	b.Do(ctx, returning(internal))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	b, clock := newTestBreaker()
+	ctx := context.Background()
+	unavailable := errors.NewUnavailableError("foo")
+
+	for i := 0; i < 4; i++ {
+		b.Do(ctx, returning(unavailable))
+	}
+	assert.Equal(t, Open, b.State())
+
+	// a failed probe reopens the breaker
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, HalfOpen, b.State())
+	assert.Equal(t, unavailable, b.Do(ctx, returning(unavailable)))
+	assert.Equal(t, Open, b.State())
+
+	// calls beyond the probes are rejected
+	clock.Advance(10 * time.Second)
+	err := b.Do(ctx, func(ctx context.Context) error {
+		assert.IsType(t, &errors.UnavailableError{}, b.Do(ctx, returning(nil)))
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreakerSpanningWindow(t *testing.T) {
+	b, clock := newTestBreaker()
+	ctx := context.Background()
+	internal := errors.NewInternalError("foo")
+
+	for i := 0; i < 3; i++ {
+		b.Do(ctx, returning(internal))
+	}
+	// a call spanning the end of the window is counted in the next one
+	b.Do(ctx, func(context.Context) error {
+		clock.Advance(time.Minute)
+		return internal
+	})
+	assert.Equal(t, Closed, b.State())
+	for i := 0; i < 3; i++ {
+		b.Do(ctx, returning(internal))
+	}
+	assert.Equal(t, Open, b.State())
+}
+
+func TestBreakerSlowCalls(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	b := New(Settings{Window: time.Second, MinRequests: 10, Clock: clock})
+	ctx := context.Background()
+
+	// calls taking longer than the window
+	var started, done sync.WaitGroup
+	release := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			b.Do(ctx, func(context.Context) error {
+				started.Done()
+				<-release
+				return errors.NewDeadlineExceededError("foo")
+			})
+		}()
+	}
+	started.Wait()
+	clock.Advance(2 * time.Second)
+	close(release)
+	done.Wait()
+	assert.Equal(t, Open, b.State())
+}
+
+func TestBreakerStaleOutcome(t *testing.T) {
+	b, clock := newTestBreaker()
+	ctx := context.Background()
+	internal := errors.NewInternalError("foo")
+
+	// a call started before the breaker opened is not counted once it is
+	// half-open
+	b.Do(ctx, func(context.Context) error {
+		for i := 0; i < 4; i++ {
+			b.Do(ctx, returning(internal))
+		}
+		assert.Equal(t, Open, b.State())
+		clock.Advance(10 * time.Second)
+		return nil
+	})
+	assert.Equal(t, HalfOpen, b.State())
+}
+
+func TestBreakerPanic(t *testing.T) {
+	b, _ := newTestBreaker()
+	for i := 0; i < 4; i++ {
+		assert.Panics(t, func() {
+			b.Do(context.Background(), func(context.Context) error { panic("foo") })
+		})
+	}
+	assert.Equal(t, Open, b.State())
+}
+
+func TestNewDefaults(t *testing.T) {
+	b := New(Settings{})
+	assert.Equal(t, 10*time.Second, b.settings.Window)
+	assert.Equal(t, 10, b.settings.MinRequests)
+	assert.Equal(t, 0.5, b.settings.FailureRatio)
+	assert.Equal(t, 30*time.Second, b.settings.OpenTimeout)
+	assert.Equal(t, 1, b.settings.HalfOpenRequests)
+	assert.Equal(t, Closed, b.State())
+
+	assert.Contains(t, b.rejection(time.Second).(*errors.UnavailableError).GetMessage(), " circuit breaker is open")
+}
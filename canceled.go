@@ -21,6 +21,8 @@ type CanceledError struct {
 	logMessage string
 	cause      error
 	stack      stack
+	reason     string
+	metadata   map[string]string
 	rpcCode    codes.Code
 }
 
@@ -30,7 +32,7 @@ func NewCanceledError(Message string, cause ...error) *CanceledError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &CanceledError{
+	e := &CanceledError{
 		Code:       499,
 		Message:    "CANCELED. Request canceled by the client.",
 		logMessage: Message,
@@ -38,6 +40,8 @@ func NewCanceledError(Message string, cause ...error) *CanceledError {
 		stack:      getTrace(),
 		rpcCode:    codes.Canceled,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -47,6 +51,9 @@ func (e *CanceledError) Error() string { return errorStr(e) }
 func (e *CanceledError) Timeout() bool { return true }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *CanceledError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -61,6 +68,18 @@ func (e *CanceledError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *CanceledError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *CanceledError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *CanceledError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *CanceledError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *CanceledError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *CanceledError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
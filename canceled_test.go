@@ -113,6 +113,24 @@ func TestCanceledErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestCanceledErrorGetReason(t *testing.T) {
+	for _, test := range CanceledErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewCanceledError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestCanceledErrorGetMetadata(t *testing.T) {
+	for _, test := range CanceledErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewCanceledError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestCanceledErrorJson(t *testing.T) {
 	for _, test := range CanceledErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -0,0 +1,194 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// A Classifier inspects an error from an external dependency and returns the
+// gRPC code identifying the correlating type from this package, or codes.OK if
+// it has no opinion about the error.
+type Classifier interface {
+	Classify(err error) codes.Code
+}
+
+// ClassifierFunc is an adapter allowing the use of an ordinary function as a
+// Classifier.
+type ClassifierFunc func(err error) codes.Code
+
+// Classify calls f(err).
+func (f ClassifierFunc) Classify(err error) codes.Code { return f(err) }
+
+// Built-in classifiers, consulted by Classify after any registered classifiers.
+var (
+	// ContextClassifier classifies errors wrapping context.Canceled as
+	// CANCELED and those wrapping context.DeadlineExceeded as
+	// DEADLINE_EXCEEDED.
+	ContextClassifier Classifier = ClassifierFunc(classifyContext)
+
+	// GRPCClassifier classifies errors implementing
+	// GRPCStatus() *status.Status, including those from this package, or
+	// wrapping such an error, by the code of their status.
+	GRPCClassifier Classifier = ClassifierFunc(classifyGRPC)
+
+	// TimeoutClassifier classifies errors implementing Timeout() bool that
+	// report a timeout as DEADLINE_EXCEEDED.
+	TimeoutClassifier Classifier = ClassifierFunc(classifyTimeout)
+
+	// TemporaryClassifier classifies errors implementing Temporary() bool that
+	// report being temporary as UNKNOWN.
+	TemporaryClassifier Classifier = ClassifierFunc(classifyTemporary)
+)
+
+// builtinClassifiers lists the built-in classifiers in the order consulted.
+var builtinClassifiers = []Classifier{
+	ContextClassifier,
+	GRPCClassifier,
+	HTTPClassifier,
+	FSClassifier,
+	NetClassifier,
+	SQLClassifier,
+	ErrnoClassifier,
+	TimeoutClassifier,
+	TemporaryClassifier,
+}
+
+// registeredClassifier is a Classifier added with RegisterClassifier.
+type registeredClassifier struct {
+	id         int
+	priority   int
+	classifier Classifier
+}
+
+// classifiers stores the registered classifiers, ordered by descending
+// priority, then by registration order. The list is replaced rather than
+// modified, so it may be loaded without locking.
+var classifiers struct {
+	sync.Mutex
+	nextID int
+	list   atomic.Pointer[[]registeredClassifier]
+}
+
+// RegisterClassifier registers c to be consulted by Classify, and so by
+// NewPassthroughError, before the built-in classifiers. Classifiers with a
+// higher priority are consulted first; those with the same priority are
+// consulted in the order registered. The first classifier with an opinion
+// decides. The returned function unregisters c.
+func RegisterClassifier(priority int, c Classifier) (unregister func()) {
+	classifiers.Lock()
+	defer classifiers.Unlock()
+
+	id := classifiers.nextID
+	classifiers.nextID++
+	var list []registeredClassifier
+	if old := classifiers.list.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, registeredClassifier{id: id, priority: priority, classifier: c})
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].priority > list[j].priority
+	})
+	classifiers.list.Store(&list)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			classifiers.Lock()
+			defer classifiers.Unlock()
+			old := *classifiers.list.Load()
+			list := make([]registeredClassifier, 0, len(old))
+			for _, r := range old {
+				if r.id != id {
+					list = append(list, r)
+				}
+			}
+			classifiers.list.Store(&list)
+		})
+	}
+}
+
+// Classify classifies an error from an external dependency, returning the
+// gRPC code identifying the correlating type from this package. Registered
+// classifiers are consulted first, then the built-in classifiers in this
+// order: ContextClassifier, GRPCClassifier, HTTPClassifier, FSClassifier,
+// NetClassifier, SQLClassifier, ErrnoClassifier, TimeoutClassifier and
+// TemporaryClassifier.
+// If no classifier has an opinion, INTERNAL is returned.
+func Classify(err error) codes.Code {
+	if c := classify(err); c != codes.OK {
+		return c
+	}
+	return codes.Internal
+}
+
+// classify classifies err as Classify does, but returns codes.OK if no
+// classifier has an opinion.
+func classify(err error) codes.Code {
+	if list := classifiers.list.Load(); list != nil {
+		for _, r := range *list {
+			if c := r.classifier.Classify(err); c != codes.OK {
+				return c
+			}
+		}
+	}
+	for _, classifier := range builtinClassifiers {
+		if c := classifier.Classify(err); c != codes.OK {
+			return c
+		}
+	}
+	return codes.OK
+}
+
+// Kind returns the gRPC code identifying the kind of err, as classified by
+// Classify, or of the first error it wraps with a known kind. If none of them
+// has a known kind, INTERNAL is returned.
+func Kind(err error) codes.Code {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if c := classify(err); c != codes.OK {
+			return c
+		}
+	}
+	return codes.Internal
+}
+
+// classifyContext implements ContextClassifier.
+func classifyContext(err error) codes.Code {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.OK
+}
+
+// classifyGRPC implements GRPCClassifier.
+func classifyGRPC(err error) codes.Code {
+	var sErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &sErr) {
+		return sErr.GRPCStatus().Code()
+	}
+	return codes.OK
+}
+
+// classifyTimeout implements TimeoutClassifier.
+func classifyTimeout(err error) codes.Code {
+	if tiErr, ok := err.(interface{ Timeout() bool }); ok && tiErr.Timeout() {
+		return codes.DeadlineExceeded
+	}
+	return codes.OK
+}
+
+// classifyTemporary implements TemporaryClassifier.
+func classifyTemporary(err error) codes.Code {
+	if teErr, ok := err.(interface{ Temporary() bool }); ok && teErr.Temporary() {
+		return codes.Unknown
+	}
+	return codes.OK
+}
@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+type timeoutError struct{ timeout, temporary bool }
+
+func (e timeoutError) Error() string   { return "timeout error" }
+func (e timeoutError) Timeout() bool   { return e.timeout }
+func (e timeoutError) Temporary() bool { return e.temporary }
+
+var errVendor = errors.New("vendor: quota exceeded")
+
+func TestBuiltinClassifiers(t *testing.T) {
+	tests := []struct {
+		classifier Classifier
+		err        error
+		code       codes.Code
+	}{
+		{ContextClassifier, context.Canceled, codes.Canceled},
+		{ContextClassifier, context.DeadlineExceeded, codes.DeadlineExceeded},
+		{ContextClassifier, fmt.Errorf("query: %w", context.Canceled), codes.Canceled},
+		{ContextClassifier, errors.New("foo"), codes.OK},
+		{GRPCClassifier, status.Error(codes.NotFound, "foo"), codes.NotFound},
+		{GRPCClassifier, NewAbortedError("foo"), codes.Aborted},
+		{GRPCClassifier, fmt.Errorf("call: %w", status.Error(codes.NotFound, "foo")), codes.NotFound},
+		{GRPCClassifier, fmt.Errorf("call: %w", NewAbortedError("foo")), codes.Aborted},
+		{GRPCClassifier, errors.New("foo"), codes.OK},
+		{TimeoutClassifier, timeoutError{timeout: true}, codes.DeadlineExceeded},
+		{TimeoutClassifier, timeoutError{}, codes.OK},
+		{TimeoutClassifier, errors.New("foo"), codes.OK},
+		{TemporaryClassifier, timeoutError{temporary: true}, codes.Unknown},
+		{TemporaryClassifier, timeoutError{}, codes.OK},
+		{TemporaryClassifier, errors.New("foo"), codes.OK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, test.classifier.Classify(test.err))
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{context.Canceled, codes.Canceled},
+		{NewCanceledError("foo"), codes.Canceled},
+		{status.Error(codes.Unavailable, "foo"), codes.Unavailable},
+		{fmt.Errorf("call: %w", status.Error(codes.Unavailable, "foo")), codes.Unavailable},
+		{fmt.Errorf("call: %w", NewNotFoundError("foo")), codes.NotFound},
+		{timeoutError{timeout: true, temporary: true}, codes.DeadlineExceeded},
+		{timeoutError{temporary: true}, codes.Unknown},
+		{errors.New("foo"), codes.Internal},
+		{errVendor, codes.Internal},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, Classify(test.err))
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{NewNotFoundError("foo"), codes.NotFound},
+		{fmt.Errorf("query: %w", NewNotFoundError("foo")), codes.NotFound},
+		{fmt.Errorf("query: %w", context.Canceled), codes.Canceled},
+		{fmt.Errorf("query: %w", errVendor), codes.Internal},
+		{errors.New("foo"), codes.Internal},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, Kind(test.err))
+	}
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	vendor := ClassifierFunc(func(err error) codes.Code {
+		if err == errVendor {
+			return codes.ResourceExhausted
+		}
+		return codes.OK
+	})
+	everything := ClassifierFunc(func(err error) codes.Code { return codes.Aborted })
+
+	unregister := RegisterClassifier(0, vendor)
+	assert.Equal(t, codes.ResourceExhausted, Classify(errVendor))
+	assert.Equal(t, codes.Canceled, Classify(context.Canceled))
+
+	// registered classifiers are consulted before built-in ones
+	unregisterEverything := RegisterClassifier(-1, everything)
+	assert.Equal(t, codes.ResourceExhausted, Classify(errVendor))
+	assert.Equal(t, codes.Aborted, Classify(context.Canceled))
+
+	// in priority order
+	unregisterOverride := RegisterClassifier(1, ClassifierFunc(func(err error) codes.Code {
+		return codes.Unavailable
+	}))
+	assert.Equal(t, codes.Unavailable, Classify(errVendor))
+	unregisterOverride()
+
+	unregisterEverything()
+	unregisterEverything()
+	assert.Equal(t, codes.Canceled, Classify(context.Canceled))
+
+	res := ClientPassthroughPolicy.NewPassthroughError("foo", errVendor)
+	assert.IsType(t, &ResourceExhaustedError{}, res)
+
+	unregister()
+	assert.Equal(t, codes.Internal, Classify(errVendor))
+}
+
+func TestRegisterClassifierConcurrently(t *testing.T) {
+	var unregisters []func()
+	for i := 0; i < 8; i++ {
+		unregisters = append(unregisters, RegisterClassifier(i%3, ClassifierFunc(func(err error) codes.Code {
+			return codes.OK
+		})))
+	}
+	defer func() {
+		for _, unregister := range unregisters {
+			unregister()
+		}
+	}()
+	unregisters[4]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.Equal(t, codes.Canceled, Classify(context.Canceled))
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				RegisterClassifier(i-j, ClassifierFunc(func(err error) codes.Code {
+					return codes.OK
+				}))()
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Causes for use with context.WithCancelCause, to record why a context was
+// canceled in the errors returned by FromContext.
+var (
+	// ErrClientDisconnected indicates the client went away.
+	ErrClientDisconnected = errors.New("client disconnected")
+
+	// ErrServerShutdown indicates the server is shutting down.
+	ErrServerShutdown = errors.New("server shutting down")
+)
+
+// Reasons recorded for context errors by FromContext
+const (
+	ReasonClientDisconnected    = "CLIENT_DISCONNECTED"
+	ReasonServerShutdown        = "SERVER_SHUTDOWN"
+	ReasonDeadlineExpired       = "DEADLINE_EXPIRED"
+	ReasonParentDeadlineExpired = "PARENT_DEADLINE_EXPIRED"
+)
+
+// startKey is the context key for the start set by WithStart.
+type startKey struct{}
+
+// started records the start of an operation set by WithStart, along with the
+// deadline the operation inherited, if any.
+type started struct {
+	at          time.Time
+	deadline    time.Time
+	hasDeadline bool
+}
+
+// WithStart returns a copy of ctx recording start as the time the operation
+// governed by ctx started, along with the deadline of ctx, if any, as the
+// deadline inherited by the operation. FromContext uses them to record the
+// elapsed time and to tell the expiry of the inherited deadline from that of
+// a deadline set for the operation itself. WithStart should wrap the context
+// received by the operation, before the operation sets any deadline of its
+// own:
+//
+//	ctx = errors.WithStart(r.Context(), time.Now())
+//	ctx, cancel := context.WithTimeout(ctx, time.Second)
+func WithStart(ctx context.Context, start time.Time) context.Context {
+	s := started{at: start}
+	s.deadline, s.hasDeadline = ctx.Deadline()
+	return context.WithValue(ctx, startKey{}, s)
+}
+
+// FromContext returns an error describing why ctx is done, or nil if it is
+// not. If ctx was canceled, a CanceledError is returned; if its deadline
+// passed, a DeadlineExceededError is returned. The error's causes are ctx.Err()
+// and, if different, context.Cause(ctx).
+//
+// The reason of the returned error distinguishes a client disconnect
+// (ErrClientDisconnected), a server shutdown (ErrServerShutdown), the expiry
+// of a deadline set for the operation (DEADLINE_EXPIRED) and the expiry of
+// the deadline the operation inherited (PARENT_DEADLINE_EXPIRED). Inherited
+// deadlines are only known if ctx was derived from a context returned by
+// WithStart; otherwise every expired deadline is reported as
+// DEADLINE_EXPIRED.
+//
+// The metadata of the returned error records the cause and, if ctx has one,
+// the deadline. The time elapsed since the operation started is recorded only
+// if ctx was derived from a context returned by WithStart.
+func FromContext(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	cause := context.Cause(ctx)
+	causes := []error{err}
+	if cause != nil && cause != err {
+		causes = append(causes, cause)
+	}
+
+	info := errorInfo{metadata: make(map[string]string)}
+	describeContext(ctx, cause, &info)
+
+	var res interface {
+		error
+		setReason(string)
+		setMetadata(map[string]string)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		if info.reason == "" {
+			info.reason = deadlineReason(ctx)
+		}
+		res = NewDeadlineExceededError(cause.Error(), causes...)
+	} else {
+		res = NewCanceledError(cause.Error(), causes...)
+	}
+	res.setReason(info.reason)
+	countReason(info.reason)
+	res.setMetadata(info.metadata)
+	observe(res, false)
+	return res
+}
+
+// deadlineReason returns the reason for the expiry of the deadline of ctx:
+// the deadline is inherited if it is no earlier than the deadline recorded by
+// WithStart, as a context derived with a later deadline keeps its parent's.
+func deadlineReason(ctx context.Context) string {
+	s, ok := ctx.Value(startKey{}).(started)
+	if !ok || !s.hasDeadline {
+		return ReasonDeadlineExpired
+	}
+	if deadline, ok := ctx.Deadline(); ok && !deadline.Before(s.deadline) {
+		return ReasonParentDeadlineExpired
+	}
+	return ReasonDeadlineExpired
+}
+
+// describeContext records the reason, cause, deadline and elapsed time of a
+// done context in info.
+func describeContext(ctx context.Context, cause error, info *errorInfo) {
+	switch {
+	case errors.Is(cause, ErrClientDisconnected):
+		info.reason = ReasonClientDisconnected
+	case errors.Is(cause, ErrServerShutdown):
+		info.reason = ReasonServerShutdown
+	}
+	info.metadata["cause"] = cause.Error()
+	if deadline, ok := ctx.Deadline(); ok {
+		info.metadata["deadline"] = deadline.Format(time.RFC3339Nano)
+	}
+	if s, ok := ctx.Value(startKey{}).(started); ok {
+		info.metadata["elapsed"] = time.Since(s.at).String()
+	}
+}
@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := FromContext(ctx)
+	canceled, ok := err.(*CanceledError)
+	if assert.True(t, ok) {
+		assert.Equal(t, "", canceled.GetReason())
+		assert.Equal(t, map[string]string{"cause": "context canceled"}, canceled.GetMetadata())
+		assert.True(t, errors.Is(canceled.GetCause(), context.Canceled))
+	}
+
+	tests := []struct {
+		cause  error
+		reason string
+	}{
+		{ErrClientDisconnected, ReasonClientDisconnected},
+		{ErrServerShutdown, ReasonServerShutdown},
+		{errors.New("foo"), ""},
+	}
+	for _, test := range tests {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(test.cause)
+		err := FromContext(ctx)
+		canceled, ok := err.(*CanceledError)
+		if assert.True(t, ok) {
+			assert.Equal(t, test.reason, canceled.GetReason())
+			assert.Equal(t, test.cause.Error(), canceled.GetMetadata()["cause"])
+			assert.True(t, errors.Is(canceled.GetCause(), context.Canceled))
+			assert.True(t, errors.Is(canceled.GetCause(), test.cause))
+		}
+	}
+}
+
+func TestFromContextDeadline(t *testing.T) {
+	start := time.Now()
+	deadline := start.Add(-time.Second)
+	ctx, cancel := context.WithDeadline(WithStart(context.Background(), start), deadline)
+	defer cancel()
+
+	err := FromContext(ctx)
+	exceeded, ok := err.(*DeadlineExceededError)
+	if assert.True(t, ok) {
+		assert.Equal(t, ReasonDeadlineExpired, exceeded.GetReason())
+		metadata := exceeded.GetMetadata()
+		assert.Equal(t, "context deadline exceeded", metadata["cause"])
+		assert.Equal(t, deadline.Format(time.RFC3339Nano), metadata["deadline"])
+		assert.NotEmpty(t, metadata["elapsed"])
+		assert.True(t, errors.Is(exceeded.GetCause(), context.DeadlineExceeded))
+	}
+
+	// the deadline inherited from the parent context expires first
+	parent, cancel := context.WithTimeoutCause(context.Background(), -time.Second, errors.New("upstream budget"))
+	defer cancel()
+	ctx, cancel = context.WithTimeout(WithStart(parent, start), time.Hour)
+	defer cancel()
+	err = FromContext(ctx)
+	exceeded, ok = err.(*DeadlineExceededError)
+	if assert.True(t, ok) {
+		assert.Equal(t, ReasonParentDeadlineExpired, exceeded.GetReason())
+		assert.Equal(t, "upstream budget", exceeded.GetMetadata()["cause"])
+		assert.True(t, errors.Is(exceeded.GetCause(), context.DeadlineExceeded))
+	}
+
+	// the operation's own deadline expires first, whatever its cause
+	parent, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	ctx, cancel = context.WithTimeoutCause(WithStart(parent, start), -time.Second, errors.New("query budget"))
+	defer cancel()
+	exceeded, ok = FromContext(ctx).(*DeadlineExceededError)
+	if assert.True(t, ok) {
+		assert.Equal(t, ReasonDeadlineExpired, exceeded.GetReason())
+		assert.Equal(t, "query budget", exceeded.GetMetadata()["cause"])
+	}
+
+	// a deadline set with a known cause
+	ctx, cancel = context.WithTimeoutCause(context.Background(), -time.Second, ErrServerShutdown)
+	defer cancel()
+	exceeded, ok = FromContext(ctx).(*DeadlineExceededError)
+	if assert.True(t, ok) {
+		assert.Equal(t, ReasonServerShutdown, exceeded.GetReason())
+	}
+
+	// elapsed is recorded only with WithStart
+	ctx, cancel = context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	exceeded, ok = FromContext(ctx).(*DeadlineExceededError)
+	if assert.True(t, ok) {
+		assert.Equal(t, ReasonDeadlineExpired, exceeded.GetReason())
+		assert.NotContains(t, exceeded.GetMetadata(), "elapsed")
+	}
+}
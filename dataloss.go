@@ -25,6 +25,8 @@ type DataLossError struct {
 	logMessage string
 	cause      error
 	stack      stack
+	reason     string
+	metadata   map[string]string
 	rpcCode    codes.Code
 }
 
@@ -34,7 +36,7 @@ func NewDataLossError(Message string, cause ...error) *DataLossError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &DataLossError{
+	e := &DataLossError{
 		Code:       500,
 		Message:    "DATA LOSS. Unrecoverable data loss or data corruption.",
 		logMessage: Message,
@@ -42,6 +44,8 @@ func NewDataLossError(Message string, cause ...error) *DataLossError {
 		stack:      getTrace(),
 		rpcCode:    codes.DataLoss,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -51,6 +55,9 @@ func (e *DataLossError) Error() string { return errorStr(e) }
 func (e *DataLossError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *DataLossError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -65,6 +72,18 @@ func (e *DataLossError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *DataLossError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *DataLossError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *DataLossError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *DataLossError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *DataLossError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *DataLossError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
@@ -113,6 +113,24 @@ func TestDataLossErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestDataLossErrorGetReason(t *testing.T) {
+	for _, test := range DataLossErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewDataLossError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestDataLossErrorGetMetadata(t *testing.T) {
+	for _, test := range DataLossErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewDataLossError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestDataLossErrorJson(t *testing.T) {
 	for _, test := range DataLossErrorTests {
 		json, _ := json.Marshal(test.err)
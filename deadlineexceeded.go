@@ -29,6 +29,8 @@ type DeadlineExceededError struct {
 	logMessage string
 	cause      error
 	stack      stack
+	reason     string
+	metadata   map[string]string
 	rpcCode    codes.Code
 }
 
@@ -38,7 +40,7 @@ func NewDeadlineExceededError(Message string, cause ...error) *DeadlineExceededE
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &DeadlineExceededError{
+	e := &DeadlineExceededError{
 		Code:       504,
 		Message:    "DEADLINE EXCEEDED. Server timeout.",
 		logMessage: Message,
@@ -46,6 +48,8 @@ func NewDeadlineExceededError(Message string, cause ...error) *DeadlineExceededE
 		stack:      getTrace(),
 		rpcCode:    codes.DeadlineExceeded,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -55,6 +59,9 @@ func (e *DeadlineExceededError) Error() string { return errorStr(e) }
 func (e *DeadlineExceededError) Timeout() bool { return true }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *DeadlineExceededError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -69,6 +76,18 @@ func (e *DeadlineExceededError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *DeadlineExceededError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *DeadlineExceededError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *DeadlineExceededError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *DeadlineExceededError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *DeadlineExceededError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *DeadlineExceededError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
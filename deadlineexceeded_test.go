@@ -113,6 +113,24 @@ func TestDeadlineExceededErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestDeadlineExceededErrorGetReason(t *testing.T) {
+	for _, test := range DeadlineExceededErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewDeadlineExceededError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestDeadlineExceededErrorGetMetadata(t *testing.T) {
+	for _, test := range DeadlineExceededErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewDeadlineExceededError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestDeadlineExceededErrorJson(t *testing.T) {
 	for _, test := range DeadlineExceededErrorTests {
 		json, _ := json.Marshal(test.err)
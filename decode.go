@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// unknownFieldPrefix prefixes the errors returned by json.Decoder for unknown
+// fields when DisallowUnknownFields is set.
+const unknownFieldPrefix = "json: unknown field "
+
+// NewDecodeError handles an error from decoding or parsing a request element,
+// such as the error returned by json.Decoder.Decode for the request body or by
+// strconv.Atoi for a query parameter. The field is the name of the request
+// element being decoded, or "" for the request body.
+//
+// Decoding errors are mapped as follows, with field violations identifying
+// the offending field:
+//
+//	*json.SyntaxError                InvalidArgumentError
+//	*json.UnmarshalTypeError         InvalidArgumentError
+//	unknown fields                   InvalidArgumentError
+//	io.EOF, io.ErrUnexpectedEOF      InvalidArgumentError
+//	*http.MaxBytesError              ResourceExhaustedError
+//	*strconv.NumError, ErrSyntax     InvalidArgumentError
+//	*strconv.NumError, ErrRange      OutOfRangeError
+//
+// Any other error, such as a failure to read the request body, is handled by
+// NewPassthroughError. NewDecodeError returns nil if err is nil.
+func NewDecodeError(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+		numErr      *strconv.NumError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		e := NewInvalidArgumentError(fmt.Sprintf("Malformed JSON at offset %d.", syntaxErr.Offset), err)
+		e.AddFieldViolation(field, syntaxErr.Error())
+		e.setMetadata(map[string]string{"offset": strconv.FormatInt(syntaxErr.Offset, 10)})
+		return e
+
+	case errors.As(err, &typeErr):
+		path := fieldPath(field, typeErr.Field)
+		description := fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value)
+		e := NewInvalidArgumentError(fmt.Sprintf("Field '%s' is invalid: %s.", path, description), err)
+		e.AddFieldViolation(path, description)
+		e.setMetadata(map[string]string{"offset": strconv.FormatInt(typeErr.Offset, 10)})
+		return e
+
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		name, uErr := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownFieldPrefix))
+		if uErr != nil {
+			name = strings.TrimPrefix(err.Error(), unknownFieldPrefix)
+		}
+		path := fieldPath(field, name)
+		e := NewInvalidArgumentError(fmt.Sprintf("Field '%s' is unknown.", path), err)
+		e.AddFieldViolation(path, "unknown field")
+		return e
+
+	case errors.As(err, &maxBytesErr):
+		e := NewResourceExhaustedError(fmt.Sprintf("Request body exceeds the limit of %d bytes.", maxBytesErr.Limit), err)
+		e.setMetadata(map[string]string{"limit": strconv.FormatInt(maxBytesErr.Limit, 10)})
+		return e
+
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		description := "empty"
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			description = "truncated"
+		}
+		name := "Request body"
+		if field != "" {
+			name = fmt.Sprintf("Field '%s'", field)
+		}
+		e := NewInvalidArgumentError(fmt.Sprintf("%s is %s.", name, description), err)
+		e.AddFieldViolation(field, description)
+		return e
+
+	case errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange):
+		description := fmt.Sprintf("value %s is out of range", strconv.Quote(numErr.Num))
+		e := NewOutOfRangeError(fmt.Sprintf("Field '%s' is invalid: %s.", field, description), err)
+		e.AddFieldViolation(field, description)
+		return e
+
+	case errors.As(err, &numErr):
+		description := fmt.Sprintf("value %s is not a valid number", strconv.Quote(numErr.Num))
+		e := NewInvalidArgumentError(fmt.Sprintf("Field '%s' is invalid: %s.", field, description), err)
+		e.AddFieldViolation(field, description)
+		return e
+	}
+
+	if field == "" {
+		return NewPassthroughError("decoding request body", err)
+	}
+	return NewPassthroughError(fmt.Sprintf("decoding field '%s'", field), err)
+}
+
+// fieldPath returns the path of the field named name within the request
+// element field.
+func fieldPath(field, name string) string {
+	switch {
+	case field == "":
+		return name
+	case name == "":
+		return field
+	}
+	return field + "." + name
+}
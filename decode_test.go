@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+type decodeTestStation struct {
+	ID        string `json:"id"`
+	Elevation int8   `json:"elevation"`
+}
+
+type decodeTestBody struct {
+	Station decodeTestStation `json:"station"`
+}
+
+func decodeBody(body string, limit int64) error {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+	dec.DisallowUnknownFields()
+	var b decodeTestBody
+	return dec.Decode(&b)
+}
+
+func TestNewDecodeError(t *testing.T) {
+	assert.Nil(t, NewDecodeError("", nil))
+
+	tests := []struct {
+		field      string
+		err        error
+		typ        error
+		message    string
+		violations []FieldViolation
+	}{
+		{
+			field:      "",
+			err:        decodeBody(`{"station": }`, 1024),
+			typ:        &InvalidArgumentError{},
+			message:    "INVALID ARGUMENT. Malformed JSON at offset 13.",
+			violations: []FieldViolation{{"", "invalid character '}' looking for beginning of value"}},
+		},
+		{
+			field:      "",
+			err:        decodeBody(`{"station": {"id": "KDEN", "elevation": "high"}}`, 1024),
+			typ:        &InvalidArgumentError{},
+			message:    "INVALID ARGUMENT. Field 'station.elevation' is invalid: expected int8, got string.",
+			violations: []FieldViolation{{"station.elevation", "expected int8, got string"}},
+		},
+		{
+			field:      "body",
+			err:        decodeBody(`{"station": {"id": "KDEN", "elevation": 1609}}`, 1024),
+			typ:        &InvalidArgumentError{},
+			message:    "INVALID ARGUMENT. Field 'body.station.elevation' is invalid: expected int8, got number 1609.",
+			violations: []FieldViolation{{"body.station.elevation", "expected int8, got number 1609"}},
+		},
+		{
+			field:      "",
+			err:        decodeBody(`{"station": {"id": "KDEN", "name": "Denver"}}`, 1024),
+			typ:        &InvalidArgumentError{},
+			message:    "INVALID ARGUMENT. Field 'name' is unknown.",
+			violations: []FieldViolation{{"name", "unknown field"}},
+		},
+		{
+			field:   "",
+			err:     decodeBody(`{"station": {"id": "KDEN", "elevation": 100}}`, 16),
+			typ:     &ResourceExhaustedError{},
+			message: "RESOURCE EXHAUSTED. Request body exceeds the limit of 16 bytes.",
+		},
+		{
+			field:      "",
+			err:        decodeBody(``, 1024),
+			typ:        &InvalidArgumentError{},
+			message:    "INVALID ARGUMENT. Request body is empty.",
+			violations: []FieldViolation{{"", "empty"}},
+		},
+		{
+			field:      "",
+			err:        decodeBody(`{"station": {`, 1024),
+			typ:        &InvalidArgumentError{},
+			message:    "INVALID ARGUMENT. Request body is truncated.",
+			violations: []FieldViolation{{"", "truncated"}},
+		},
+		{
+			field:      "limit",
+			err:        func() error { _, err := strconv.Atoi("ten"); return err }(),
+			typ:        &InvalidArgumentError{},
+			message:    `INVALID ARGUMENT. Field 'limit' is invalid: value "ten" is not a valid number.`,
+			violations: []FieldViolation{{"limit", `value "ten" is not a valid number`}},
+		},
+		{
+			field:      "elevation",
+			err:        func() error { _, err := strconv.ParseInt("1609", 10, 8); return err }(),
+			typ:        &OutOfRangeError{},
+			message:    `OUT OF RANGE. Field 'elevation' is invalid: value "1609" is out of range.`,
+			violations: []FieldViolation{{"elevation", `value "1609" is out of range`}},
+		},
+		{
+			field:   "",
+			err:     io.ErrClosedPipe,
+			typ:     &InternalError{},
+			message: "INTERNAL ERROR. decoding request body",
+		},
+	}
+	for _, test := range tests {
+		res := NewDecodeError(test.field, test.err)
+		assert.IsType(t, test.typ, res)
+		assert.Equal(t, test.message, res.(interface{ GetMessage() string }).GetMessage())
+		if test.violations != nil {
+			assert.Equal(t, test.violations, res.(interface{ GetFieldViolations() []FieldViolation }).GetFieldViolations())
+		}
+		assert.True(t, errors.Is(res.(interface{ GetCause() error }).GetCause(), test.err))
+	}
+}
+
+func TestNewDecodeErrorMetadata(t *testing.T) {
+	res := NewDecodeError("", decodeBody(`{"station": }`, 1024))
+	assert.Equal(t, map[string]string{"offset": "13"}, res.(*InvalidArgumentError).GetMetadata())
+
+	res = NewDecodeError("", decodeBody(`{"station": {"id": "KDEN", "elevation": 100}}`, 16))
+	assert.Equal(t, map[string]string{"limit": "16"}, res.(*ResourceExhaustedError).GetMetadata())
+}
@@ -0,0 +1,154 @@
+package errors
+
+import (
+	"errors"
+	"time"
+
+	codes "google.golang.org/grpc/codes"
+)
+
+// DispositionAction is the action a message queue consumer should take with a
+// message whose processing returned an error.
+type DispositionAction int
+
+// Disposition actions returned by Disposition
+const (
+	// Ack indicates the message was processed and should be acknowledged.
+	Ack DispositionAction = iota
+
+	// RetryLater indicates the message should be redelivered after a delay.
+	RetryLater
+
+	// DeadLetter indicates the message cannot be processed without
+	// intervention and should be moved to a dead-letter queue.
+	DeadLetter
+
+	// Drop indicates the message should be discarded, such as a duplicate of
+	// a message already processed.
+	Drop
+)
+
+// String returns the name of a.
+func (a DispositionAction) String() string {
+	switch a {
+	case Ack:
+		return "ACK"
+	case RetryLater:
+		return "RETRY_LATER"
+	case DeadLetter:
+		return "DEAD_LETTER"
+	case Drop:
+		return "DROP"
+	}
+	return "UNKNOWN"
+}
+
+// DispositionDecision is the result of Disposition.
+type DispositionDecision struct {
+	// Action is the action the consumer should take.
+	Action DispositionAction
+
+	// Delay is how long to wait before redelivering the message for
+	// RetryLater, where zero means immediately.
+	Delay time.Duration
+}
+
+// DispositionPolicy determines the disposition of messages by the error
+// returned when processing them. The zero DispositionPolicy decides by the
+// kind of error alone (see Disposition).
+type DispositionPolicy struct {
+	reasons     map[string]DispositionDecision
+	maxAttempts int
+}
+
+// dispositionPolicy stores the global policy used by Disposition.
+var dispositionPolicy DispositionPolicy
+
+// SetDispositionPolicy changes the global policy used by Disposition.
+func SetDispositionPolicy(p DispositionPolicy) { dispositionPolicy = p }
+
+// WithReason returns a copy of p deciding d for errors with the
+// machine-readable reason, whatever their kind.
+func (p DispositionPolicy) WithReason(reason string, d DispositionDecision) DispositionPolicy {
+	reasons := make(map[string]DispositionDecision, len(p.reasons)+1)
+	for r, rd := range p.reasons {
+		reasons[r] = rd
+	}
+	reasons[reason] = d
+	p.reasons = reasons
+	return p
+}
+
+// WithMaxAttempts returns a copy of p escalating RetryLater decisions to
+// DeadLetter once a message has been delivered n times. Zero means messages
+// are retried without limit.
+func (p DispositionPolicy) WithMaxAttempts(n int) DispositionPolicy {
+	p.maxAttempts = n
+	return p
+}
+
+// Disposition decides the disposition of a message whose processing returned
+// err on its attempt-th delivery, counting from 1. If p has a decision for the
+// reason of err, that decision is used; otherwise the decision is made by the
+// kind of err (see Disposition). RetryLater decisions are escalated to
+// DeadLetter once attempt reaches the maximum attempts of p.
+func (p DispositionPolicy) Disposition(err error, attempt int) DispositionDecision {
+	if err == nil {
+		return DispositionDecision{Action: Ack}
+	}
+	reason := reasonOf(err)
+	d, ok := p.reasons[reason]
+	if !ok || reason == "" {
+		d = kindDisposition(err)
+	}
+	if d.Action == RetryLater && p.maxAttempts > 0 && attempt >= p.maxAttempts {
+		return DispositionDecision{Action: DeadLetter}
+	}
+	return d
+}
+
+// Disposition decides the disposition of a message whose processing returned
+// err using the global disposition policy (see SetDispositionPolicy). As the
+// delivery attempt is unknown, decisions are never escalated to DeadLetter;
+// use DispositionPolicy.Disposition for that. With the default policy, the
+// decision is made by the kind of err, or, if no classifier has an opinion
+// about err, by the kind of the first error it wraps that one has an opinion
+// about:
+//
+//	nil                                       Ack
+//	UNAVAILABLE, RESOURCE_EXHAUSTED           RetryLater, after any retry delay
+//	DEADLINE_EXCEEDED, ABORTED, UNKNOWN       RetryLater, after any retry delay
+//	CANCELED                                  RetryLater, immediately
+//	ALREADY_EXISTS                            Drop
+//	other                                     DeadLetter
+func Disposition(err error) DispositionDecision {
+	return dispositionPolicy.Disposition(err, 0)
+}
+
+// kindDisposition decides the disposition of a message by the kind of err.
+func kindDisposition(err error) DispositionDecision {
+	switch Kind(err) {
+	case codes.Unavailable,
+		codes.ResourceExhausted,
+		codes.DeadlineExceeded,
+		codes.Aborted,
+		codes.Unknown:
+		delay, _ := RetryDelay(err)
+		return DispositionDecision{Action: RetryLater, Delay: delay}
+	case codes.Canceled:
+		return DispositionDecision{Action: RetryLater}
+	case codes.AlreadyExists:
+		return DispositionDecision{Action: Drop}
+	}
+	return DispositionDecision{Action: DeadLetter}
+}
+
+// reasonOf returns the machine-readable reason of err, or of the first error
+// it wraps that has one.
+func reasonOf(err error) string {
+	var reasonErr interface{ GetReason() string }
+	if errors.As(err, &reasonErr) {
+		return reasonErr.GetReason()
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+func TestDisposition(t *testing.T) {
+	exhausted := NewResourceExhaustedError("foo")
+	exhausted.SetRetryDelay(time.Second)
+
+	tests := []struct {
+		err      error
+		decision DispositionDecision
+	}{
+		{nil, DispositionDecision{Action: Ack}},
+		{NewUnavailableError("foo"), DispositionDecision{Action: RetryLater}},
+		{exhausted, DispositionDecision{Action: RetryLater, Delay: time.Second}},
+		{fmt.Errorf("handling: %w", exhausted), DispositionDecision{Action: RetryLater, Delay: time.Second}},
+		{NewDeadlineExceededError("foo"), DispositionDecision{Action: RetryLater}},
+		{NewAbortedError("foo"), DispositionDecision{Action: RetryLater}},
+		{NewUnknownError("foo"), DispositionDecision{Action: RetryLater}},
+		{NewCanceledError("foo"), DispositionDecision{Action: RetryLater}},
+		{NewAlreadyExistsError("foo"), DispositionDecision{Action: Drop}},
+		{NewInvalidArgumentError("foo"), DispositionDecision{Action: DeadLetter}},
+		{NewDataLossError("foo"), DispositionDecision{Action: DeadLetter}},
+		{NewNotFoundError("foo"), DispositionDecision{Action: DeadLetter}},
+		{NewInternalError("foo"), DispositionDecision{Action: DeadLetter}},
+		{status.Error(codes.Unavailable, "foo"), DispositionDecision{Action: RetryLater}},
+		{fmt.Errorf("handling: %w", context.DeadlineExceeded), DispositionDecision{Action: RetryLater}},
+		{errors.New("foo"), DispositionDecision{Action: DeadLetter}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.decision, Disposition(test.err), fmt.Sprint(test.err))
+	}
+}
+
+func TestDispositionPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrServerShutdown)
+	shutdown := FromContext(ctx)
+
+	p := DispositionPolicy{}.
+		WithReason(ReasonServerShutdown, DispositionDecision{Action: RetryLater, Delay: time.Minute}).
+		WithReason(ReasonDNSNotFound, DispositionDecision{Action: Drop})
+
+	assert.Equal(t, DispositionDecision{Action: RetryLater}, Disposition(shutdown))
+	assert.Equal(t, DispositionDecision{Action: RetryLater, Delay: time.Minute}, p.Disposition(shutdown, 1))
+	assert.Equal(t, DispositionDecision{Action: RetryLater, Delay: time.Minute}, p.Disposition(fmt.Errorf("handling: %w", shutdown), 1))
+	assert.Equal(t, DispositionDecision{Action: DeadLetter}, p.Disposition(NewInternalError("foo"), 1))
+
+	// escalation after the maximum attempts
+	p = p.WithMaxAttempts(3)
+	assert.Equal(t, RetryLater, p.Disposition(shutdown, 2).Action)
+	assert.Equal(t, DispositionDecision{Action: DeadLetter}, p.Disposition(shutdown, 3))
+	assert.Equal(t, DispositionDecision{Action: DeadLetter}, p.Disposition(NewUnavailableError("foo"), 4))
+	assert.Equal(t, DispositionDecision{Action: Drop}, p.Disposition(NewAlreadyExistsError("foo"), 4))
+	assert.Equal(t, DispositionDecision{Action: Ack}, p.Disposition(nil, 4))
+
+	// errors without a reason are not affected by an empty reason
+	p = DispositionPolicy{}.WithReason("", DispositionDecision{Action: Drop})
+	assert.Equal(t, DeadLetter, p.Disposition(NewInternalError("foo"), 1).Action)
+}
+
+func TestSetDispositionPolicy(t *testing.T) {
+	defer SetDispositionPolicy(DispositionPolicy{})
+
+	SetDispositionPolicy(DispositionPolicy{}.WithMaxAttempts(1))
+	assert.Equal(t, DispositionDecision{Action: RetryLater}, Disposition(NewUnavailableError("foo")))
+	SetDispositionPolicy(DispositionPolicy{}.WithReason(ReasonServerShutdown, DispositionDecision{Action: Drop}))
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrServerShutdown)
+	assert.Equal(t, DispositionDecision{Action: Drop}, Disposition(FromContext(ctx)))
+}
+
+func TestDispositionActionString(t *testing.T) {
+	assert.Equal(t, "ACK", Ack.String())
+	assert.Equal(t, "RETRY_LATER", RetryLater.String())
+	assert.Equal(t, "DEAD_LETTER", DeadLetter.String())
+	assert.Equal(t, "DROP", Drop.String())
+	assert.Equal(t, "UNKNOWN", DispositionAction(-1).String())
+}
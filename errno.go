@@ -0,0 +1,71 @@
+//go:build unix
+
+package errors
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	codes "google.golang.org/grpc/codes"
+)
+
+// ErrnoClassifier classifies syscall.Errno values, including those wrapped in
+// an *os.SyscallError or *net.OpError, by errno family:
+//
+//	ECONNREFUSED, ECONNRESET, ECONNABORTED,
+//	EHOSTUNREACH, ENETUNREACH              UNAVAILABLE
+//	ETIMEDOUT                              DEADLINE_EXCEEDED
+//	ENOSPC, EMFILE, ENFILE, EDQUOT         RESOURCE_EXHAUSTED
+//	EACCES, EPERM                          PERMISSION_DENIED
+//	EIO                                    DATA_LOSS
+//
+// The name of the errno, and the system call of any *os.SyscallError, are
+// recorded in the metadata of errors returned by NewPassthroughError.
+var ErrnoClassifier Classifier = ClassifierFunc(classifyErrno)
+
+// errnos maps the errno values known to ErrnoClassifier to their names and
+// codes.
+var errnos = map[syscall.Errno]struct {
+	name string
+	code codes.Code
+}{
+	syscall.ECONNREFUSED: {"ECONNREFUSED", codes.Unavailable},
+	syscall.ECONNRESET:   {"ECONNRESET", codes.Unavailable},
+	syscall.ECONNABORTED: {"ECONNABORTED", codes.Unavailable},
+	syscall.EHOSTUNREACH: {"EHOSTUNREACH", codes.Unavailable},
+	syscall.ENETUNREACH:  {"ENETUNREACH", codes.Unavailable},
+	syscall.ETIMEDOUT:    {"ETIMEDOUT", codes.DeadlineExceeded},
+	syscall.ENOSPC:       {"ENOSPC", codes.ResourceExhausted},
+	syscall.EMFILE:       {"EMFILE", codes.ResourceExhausted},
+	syscall.ENFILE:       {"ENFILE", codes.ResourceExhausted},
+	syscall.EDQUOT:       {"EDQUOT", codes.ResourceExhausted},
+	syscall.EACCES:       {"EACCES", codes.PermissionDenied},
+	syscall.EPERM:        {"EPERM", codes.PermissionDenied},
+	syscall.EIO:          {"EIO", codes.DataLoss},
+}
+
+// classifyErrno implements ErrnoClassifier.
+func classifyErrno(err error) codes.Code {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		if e, ok := errnos[errno]; ok {
+			return e.code
+		}
+	}
+	return codes.OK
+}
+
+// describeErrno records the errno name and system call of err in info.
+func describeErrno(err error, info *errorInfo) {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		if e, ok := errnos[errno]; ok {
+			info.metadata["errno"] = e.name
+		}
+	}
+	var syscallErr *os.SyscallError
+	if errors.As(err, &syscallErr) {
+		info.metadata["syscall"] = syscallErr.Syscall
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package errors
+
+import (
+	codes "google.golang.org/grpc/codes"
+)
+
+// ErrnoClassifier classifies syscall.Errno values by errno family. It has no
+// opinion on platforms other than unix.
+var ErrnoClassifier Classifier = ClassifierFunc(func(err error) codes.Code { return codes.OK })
+
+// describeErrno records nothing on platforms other than unix.
+func describeErrno(err error, info *errorInfo) {}
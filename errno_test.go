@@ -0,0 +1,75 @@
+//go:build unix
+
+package errors
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+)
+
+func TestErrnoClassifier(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{syscall.ECONNREFUSED, codes.Unavailable},
+		{os.NewSyscallError("connect", syscall.ECONNRESET), codes.Unavailable},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)}, codes.Unavailable},
+		{syscall.ETIMEDOUT, codes.DeadlineExceeded},
+		{os.NewSyscallError("write", syscall.ENOSPC), codes.ResourceExhausted},
+		{syscall.EMFILE, codes.ResourceExhausted},
+		{syscall.EDQUOT, codes.ResourceExhausted},
+		{syscall.EACCES, codes.PermissionDenied},
+		{syscall.EPERM, codes.PermissionDenied},
+		{syscall.EIO, codes.DataLoss},
+		{syscall.EINVAL, codes.OK},
+		{errors.New("foo"), codes.OK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, ErrnoClassifier.Classify(test.err))
+	}
+}
+
+func TestClassifyNetErrno(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, codes.Unavailable},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("socket", syscall.EMFILE)}, codes.ResourceExhausted},
+		{&net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.ENOSPC)}, codes.ResourceExhausted},
+		{&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.EIO)}, codes.DataLoss},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ETIMEDOUT)}, codes.DeadlineExceeded},
+		{&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.EINVAL)}, codes.Unknown},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EINVAL)}, codes.Unavailable},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, Classify(test.err), test.err.Error())
+	}
+}
+
+func TestPassthroughErrnoMetadata(t *testing.T) {
+	err := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	res := NewPassthroughError("foo", err)
+	unavailable, ok := res.(*UnavailableError)
+	if assert.True(t, ok) {
+		assert.Equal(t, "ECONNREFUSED", unavailable.GetMetadata()["errno"])
+		assert.Equal(t, "connect", unavailable.GetMetadata()["syscall"])
+	}
+
+	res = ClientPassthroughPolicy.NewPassthroughError("foo", os.NewSyscallError("write", syscall.ENOSPC))
+	assert.IsType(t, &ResourceExhaustedError{}, res)
+	assert.Equal(t, "ENOSPC", res.(*ResourceExhaustedError).GetMetadata()["errno"])
+
+	// errnos known to the filesystem classifier keep its classification
+	res = ClientPassthroughPolicy.NewPassthroughError("foo", &os.PathError{Op: "open", Path: "foo", Err: syscall.EACCES})
+	assert.Equal(t,
+		map[string]string{"op": "open", "path": "foo", "errno": "EACCES"},
+		res.(*PermissionDeniedError).GetMetadata())
+}
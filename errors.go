@@ -9,198 +9,231 @@ import (
 	status "google.golang.org/grpc/status"
 )
 
-// Errors is a container for multiple errors and implements the error interface
+// Errors is a container for multiple errors and implements the error interface.
+// It is safe for concurrent use by multiple goroutines.
+//
+// By default Errors retains every non-nil error appended to it. Large
+// aggregates may be bounded with SetMaxLen and SetDedup; errors that are not
+// retained are still counted by Len, Omitted and Summary, and by the code of
+// the Errors.
 type Errors struct {
-	sync.RWMutex
-	errs []error
+	mu            sync.RWMutex
+	errs          []error
+	maxLen        int
+	dedup         bool
+	flattenJoined bool
+	seen          map[string]struct{}
+	omitted       map[codes.Code]int
 }
 
 // NewErrors returns an error that consists of multiple errors.
 func NewErrors(errs ...error) *Errors {
-	e := Errors{errs: errs}
+	// copy errs so the caller's slice isn't shared with e
+	e := Errors{errs: append([]error(nil), errs...)}
 	return &e
 }
 
 // Error implements the error interface
-func (e Errors) Error() string {
-	if len(e.errs) <= 0 {
+func (e *Errors) Error() string {
+	errs, omitted := e.state()
+	return errorsStr(errs, countOmitted(omitted))
+}
+
+// errorsStr returns a string representation of errs, followed by a count of
+// any omitted errors.
+func errorsStr(errs []error, omitted int) string {
+	if errCount(errs) <= 0 && omitted <= 0 {
 		return ""
-	} else if len(e.errs) == 1 {
-		return e.errs[0].Error()
+	} else if len(errs) == 1 && omitted <= 0 {
+		return errs[0].Error()
 	}
-	logWithNumber := make([]string, len(e.errs))
-	for i, l := range e.errs {
+	logWithNumber := make([]string, len(errs), len(errs)+1)
+	for i, l := range errs {
 		if l != nil {
 			logWithNumber[i] = fmt.Sprintf("#%d: %s", i+1, l.Error())
 		}
 	}
+	if omitted > 0 {
+		logWithNumber = append(logWithNumber, fmt.Sprintf("and %d more", omitted))
+	}
 
 	return fmt.Sprintf("MULTIPLE ERRORS.\n%s", strings.Join(logWithNumber, "\n"))
 }
 
-// Len returns the number of errors in e
+// Len returns the number of errors in e, including any errors omitted because
+// of the limits set by SetMaxLen or SetDedup. Omitted errors are not returned
+// by Snapshot, Pop or Shift, but are discarded by them once no other errors
+// remain, so e may be drained with
+//
+//	for e.Len() > 0 {
+//		e.Pop()
+//	}
 func (e *Errors) Len() int {
 	if e == nil {
 		return 0
 	}
-	if len(e.errs) == 1 {
-		err := e.peek()
-		if err == nil {
-			return 0
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return errCount(e.errs) + countOmitted(e.omitted)
+}
+
+// Snapshot returns a copy of the errors in e. The returned slice is not
+// affected by subsequent changes to e, so it may be iterated safely while
+// other goroutines continue to modify e.
+func (e *Errors) Snapshot() []error {
+	errs, _ := e.state()
+	return errs
+}
+
+// state returns a copy of the errors in e and of the counts, by code, of the
+// errors omitted from e.
+func (e *Errors) state() ([]error, map[codes.Code]int) {
+	if e == nil {
+		return nil, nil
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var omitted map[codes.Code]int
+	if len(e.omitted) > 0 {
+		omitted = make(map[codes.Code]int, len(e.omitted))
+		for c, n := range e.omitted {
+			omitted[c] = n
 		}
 	}
-	return len(e.errs)
+	if len(e.errs) == 0 {
+		return nil, omitted
+	}
+	errs := make([]error, len(e.errs))
+	copy(errs, e.errs)
+	return errs, omitted
+}
+
+// countOmitted returns the number of omitted errors counted by code in
+// omitted.
+func countOmitted(omitted map[codes.Code]int) int {
+	n := 0
+	for _, c := range omitted {
+		n += c
+	}
+	return n
 }
 
-// Pop removes and returns the last error from Errors
+// Append adds errs to e, skipping any nil errors.
 func (e *Errors) Append(errs ...error) {
 	if len(errs) == 0 {
 		return
 	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.flattenJoined {
+		errs = flattenAll(errs)
+	}
 	for _, err := range errs {
 		if err == nil {
 			continue
 		}
-		e.Lock()
-		e.errs = append(e.errs, err)
-		e.Unlock()
+		e.retain(err)
 	}
-	return
 }
 
-// Merge adds in to e
+// Merge adds in to e, including the counts of any errors omitted from in.
 func (e *Errors) Merge(in *Errors) {
 	if in == nil {
 		return
 	}
-	e.Append(in.errs...)
-	return
+	// snapshot in before locking e, so that merging e into itself can't deadlock
+	errs, omitted := in.state()
+	e.Append(errs...)
+	if len(omitted) == 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.omitted == nil {
+		e.omitted = make(map[codes.Code]int, len(omitted))
+	}
+	for c, n := range omitted {
+		e.omitted[c] += n
+	}
 }
 
-// Pop removes and returns the last error from Errors
+// Pop removes and returns the last error from Errors. If only omitted errors
+// remain (see Omitted), they are discarded and nil is returned.
 func (e *Errors) Pop() error {
-	e.Lock()
-	defer e.Unlock()
-	if e.Len() > 0 {
-		err := (e.errs)[len(e.errs)-1]
-		e.errs = (e.errs)[:len(e.errs)-1]
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if errCount(e.errs) > 0 {
+		err := e.errs[len(e.errs)-1]
+		e.errs = e.errs[:len(e.errs)-1]
+		e.forget(err)
 		return err
 	}
+	e.omitted = nil
 	return nil
 }
 
-// Shift removes and returns the first error from Errors
+// Shift removes and returns the first error from Errors. If only omitted errors
+// remain (see Omitted), they are discarded and nil is returned.
 func (e *Errors) Shift() error {
-	e.Lock()
-	defer e.Unlock()
-	if e.Len() > 0 {
-		err := (e.errs)[0]
-		e.errs = (e.errs)[1:]
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if errCount(e.errs) > 0 {
+		err := e.errs[0]
+		e.errs = e.errs[1:]
+		e.forget(err)
 		return err
 	}
+	e.omitted = nil
 	return nil
 }
 
-// Timeout indicates if this error is the result of a timeout.
+// Timeout indicates if this error is the result of a timeout, which is the
+// case when every error in e is the result of a timeout.
 func (e *Errors) Timeout() bool {
-	err := e.peekLocked()
-	if err != nil {
+	return e.all(func(err error) bool {
 		wxErr, ok := err.(interface{ Timeout() bool })
-		if ok {
-			return wxErr.Timeout()
-		}
-	}
-	return false
+		return ok && wxErr.Timeout()
+	})
 }
 
-// Temporary indicates if this error is potentially recoverable.
+// Temporary indicates if this error is potentially recoverable, which is the
+// case when every error in e is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *Errors) Temporary() bool {
-	err := e.peekLocked()
-	if err != nil {
+	return e.all(func(err error) bool {
 		wxErr, ok := err.(interface{ Temporary() bool })
-		if ok {
-			return wxErr.Temporary()
-		}
-	}
-	return false
+		return ok && wxErr.Temporary()
+	})
 }
 
-// GetCode returns the HTTP status code associated with this error.
+// GetCode returns the HTTP status code associated with this error. When e
+// holds more than one error, the code is chosen by the global aggregation
+// policy (see SetAggregationPolicy).
 func (e *Errors) GetCode() int {
 
-	if e == nil {
+	errs, omitted := e.state()
+	if errCount(errs) == 0 && len(omitted) == 0 {
 		return 200
 	}
 
-	e.RLock()
-	defer e.RUnlock()
-	if e.Len() == 0 {
-		return 200
-	} else if e.Len() == 1 {
-
-		err := e.peek()
-
-		wxErr, ok := err.(interface{ GetCode() int })
-		if ok {
-			return wxErr.GetCode()
-		}
-
-		// validate this is a gRPC error
-		_, ok = err.(interface{ GRPCStatus() *status.Status })
-		if ok {
-			switch status.Code(err) {
-			case codes.Aborted:
-				return 409
-			case codes.AlreadyExists:
-				return 409
-			case codes.Canceled:
-				return 499
-			case codes.DataLoss:
-				return 500
-			case codes.DeadlineExceeded:
-				return 504
-			case codes.FailedPrecondition:
-				return 400
-			case codes.Internal:
-				return 500
-			case codes.InvalidArgument:
-				return 400
-			case codes.NotFound:
-				return 404
-			case codes.OutOfRange:
-				return 400
-			case codes.PermissionDenied:
-				return 403
-			case codes.ResourceExhausted:
-				return 429
-			case codes.Unauthenticated:
-				return 401
-			case codes.Unavailable:
-				return 503
-			case codes.Unimplemented:
-				return 501
-			case codes.Unknown:
-				return 500
-			}
-		}
+	code, err := aggregate(errs, omitted)
+	wxErr, ok := err.(interface{ GetCode() int })
+	if ok {
+		return wxErr.GetCode()
 	}
-
-	return 500
+	return httpCode(code)
 }
 
 // GetMessage returns the message associated with this error.
 func (e *Errors) GetMessage() string {
 
-	if e == nil {
-		return ""
-	}
-
-	e.RLock()
-	defer e.RUnlock()
-	if e.Len() == 0 {
+	errs, omitted := e.state()
+	if errCount(errs) == 0 && len(omitted) == 0 {
 		return ""
-	} else if e.Len() == 1 {
-		err := e.peek()
+	} else if len(errs) == 1 && len(omitted) == 0 {
+		err := errs[0]
 		wxErr, ok := err.(interface{ GetMessage() string })
 		if ok {
 			return wxErr.GetMessage()
@@ -213,16 +246,11 @@ func (e *Errors) GetMessage() string {
 // GetCause returns any causal errors associated with this error.
 func (e *Errors) GetCause() error {
 
-	if e == nil {
-		return nil
-	}
-
-	e.RLock()
-	defer e.RUnlock()
-	if e.Len() == 0 {
+	errs, omitted := e.state()
+	if errCount(errs) == 0 && len(omitted) == 0 {
 		return nil
-	} else if e.Len() == 1 {
-		err := e.peek()
+	} else if len(errs) == 1 && len(omitted) == 0 {
+		err := errs[0]
 		wxErr, ok := err.(interface{ GetCause() error })
 		if ok {
 			return wxErr.GetCause()
@@ -237,14 +265,9 @@ func (e *Errors) GetStack() stack {
 
 	var s stack
 
-	if e == nil {
-		return s
-	}
-
-	e.RLock()
-	defer e.RUnlock()
-	if e.Len() == 1 {
-		err := e.peek()
+	errs, omitted := e.state()
+	if errCount(errs) == 1 && len(omitted) == 0 {
+		err := errs[0]
 		wxErr, ok := err.(interface{ GetStack() stack })
 		if ok {
 			return wxErr.GetStack()
@@ -253,46 +276,47 @@ func (e *Errors) GetStack() stack {
 	return s
 }
 
-// GRPCStatus implements an interface required to return proper GRPC status codes
+// GRPCStatus implements an interface required to return proper GRPC status codes.
+// When e holds more than one error, the code is chosen by the global
+// aggregation policy (see SetAggregationPolicy) and the status of each error
+// is attached as a detail entry.
 func (e *Errors) GRPCStatus() *status.Status {
 
-	if e == nil {
+	errs, omitted := e.state()
+	if errCount(errs) == 0 && len(omitted) == 0 {
 		return nil
-	}
-
-	e.RLock()
-	defer e.RUnlock()
-	if e.Len() == 0 {
-		return nil
-	} else if e.Len() == 1 {
-		err := e.peek()
+	} else if len(errs) == 1 && len(omitted) == 0 {
+		err := errs[0]
 		grpcErr, ok := err.(interface{ GRPCStatus() *status.Status })
 		if ok {
 			return grpcErr.GRPCStatus()
 		}
+		return status.New(codes.Unknown, errorsStr(errs, 0))
 	}
-	return status.New(codes.Unknown, e.Error())
+	return aggregateStatus(errs, omitted, errorsStr(errs, countOmitted(omitted)))
 }
 
-// peek returns the first error in e, but leaves it in the slice
-func (e *Errors) peek() error {
-	if e == nil {
-		return nil
-	}
-	if len(e.errs) > 0 {
-		return e.errs[0]
+// all reports whether e holds at least one error and f returns true for every
+// non-nil error in e.
+func (e *Errors) all(f func(error) bool) bool {
+	found := false
+	for _, err := range e.Snapshot() {
+		if err == nil {
+			continue
+		}
+		if !f(err) {
+			return false
+		}
+		found = true
 	}
-	return nil
+	return found
 }
 
-// peek returns the first error in e, but leaves it in the slice with a read lock.
-func (e *Errors) peekLocked() error {
-	if e == nil {
-		return nil
+// errCount returns the number of errors in errs. A slice holding a single nil
+// error is considered empty.
+func errCount(errs []error) int {
+	if len(errs) == 1 && errs[0] == nil {
+		return 0
 	}
-
-	e.RLock()
-	defer e.RUnlock()
-	err := e.peek()
-	return err
+	return len(errs)
 }
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"sync"
 	"testing"
 
 	assert "github.com/stretchr/testify/assert"
@@ -450,27 +451,74 @@ func TestErrorsGRPCStatus(t *testing.T) {
 		assert.Nil(t, test.errs.GRPCStatus())
 	}
 }
-func TestErrorsPeekLocked(t *testing.T) {
-	tests := []struct {
-		errs *Errors
-		err  error
-	}{
-		{
-			NewErrors(errors.New("foo")),
-			errors.New("foo"),
-		},
-		{
-			NewErrors(errors.New("foo"), errors.New("bar")),
-			errors.New("foo"),
-		},
-	}
-	for _, test := range tests {
-		err := test.errs.peekLocked()
-		assert.Equal(t, test.err.Error(), err.Error())
-	}
+func TestErrorsSnapshot(t *testing.T) {
+	var nilErrs *Errors
+	assert.Nil(t, nilErrs.Snapshot())
+	assert.Nil(t, NewErrors().Snapshot())
+
+	errs := NewErrors(errors.New("foo"), errors.New("bar"))
+	snap := errs.Snapshot()
+	errs.Append(errors.New("baz"))
+	errs.Shift()
+	assert.Equal(t, 2, len(snap))
+	assert.Equal(t, "foo", snap[0].Error())
+	assert.Equal(t, "bar", snap[1].Error())
+
+	snap[0] = errors.New("bat")
+	assert.Equal(t, "bar", errs.Snapshot()[0].Error())
+}
+
+func TestErrorsConcurrency(t *testing.T) {
+	const (
+		workers = 16
+		rounds  = 100
+	)
 
-	// test nil response
 	errs := NewErrors()
-	assert.Equal(t, nil, errs.peekLocked())
+	other := NewErrors(NewNotFoundError("foo"), NewUnavailableError("bar"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < rounds; j++ {
+				switch (i + j) % 8 {
+				case 0:
+					errs.Append(errors.New("foo"))
+				case 1:
+					errs.Merge(other)
+				case 2:
+					errs.Merge(NewErrors(errors.New("baz"), nil))
+					errs.Pop()
+				case 3:
+					errs.Pop()
+				case 4:
+					errs.Shift()
+				case 5:
+					_ = errs.Error()
+					_ = errs.GetMessage()
+				case 6:
+					_ = errs.GetCode()
+					_ = errs.GRPCStatus()
+					_ = errs.Timeout()
+					_ = errs.Temporary()
+				case 7:
+					for _, err := range errs.Snapshot() {
+						assert.NotNil(t, err)
+					}
+					_ = errs.Len()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// other is merged concurrently but never modified
+	assert.Equal(t, 2, other.Len())
 
+	// merging e into itself must not deadlock
+	errs = NewErrors(errors.New("foo"))
+	errs.Merge(errs)
+	assert.Equal(t, 2, errs.Len())
 }
@@ -28,11 +28,13 @@ import (
 //
 // RPC Mapping: FAILED_PRECONDITION
 type FailedPreconditionError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code     int    `json:"errorCode"`
+	Message  string `json:"errorMessage"`
+	cause    error
+	stack    stack
+	reason   string
+	metadata map[string]string
+	rpcCode  codes.Code
 }
 
 // NewFailedPreconditionError returns a new FailedPreconditionError.
@@ -41,13 +43,15 @@ func NewFailedPreconditionError(Message string, cause ...error) *FailedPrecondit
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &FailedPreconditionError{
+	e := &FailedPreconditionError{
 		Code:    400,
 		Message: "FAILED PRECONDITION. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.FailedPrecondition,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -57,6 +61,9 @@ func (e *FailedPreconditionError) Error() string { return errorStr(e) }
 func (e *FailedPreconditionError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *FailedPreconditionError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -71,6 +78,18 @@ func (e *FailedPreconditionError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *FailedPreconditionError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *FailedPreconditionError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *FailedPreconditionError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *FailedPreconditionError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *FailedPreconditionError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *FailedPreconditionError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
@@ -113,6 +113,24 @@ func TestFailedPreconditionErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestFailedPreconditionErrorGetReason(t *testing.T) {
+	for _, test := range FailedPreconditionErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewFailedPreconditionError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestFailedPreconditionErrorGetMetadata(t *testing.T) {
+	for _, test := range FailedPreconditionErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewFailedPreconditionError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestFailedPreconditionErrorJson(t *testing.T) {
 	for _, test := range FailedPreconditionErrorTests {
 		json, _ := json.Marshal(test.err)
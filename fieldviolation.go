@@ -0,0 +1,36 @@
+package errors
+
+import (
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+	status "google.golang.org/grpc/status"
+)
+
+// FieldViolation describes a single bad request field.
+type FieldViolation struct {
+	// Field is a path leading to a field in the request body, such as
+	// "stations.0.elevation", or the name of a request parameter.
+	Field string `json:"field"`
+
+	// Description describes why the request element is bad.
+	Description string `json:"description"`
+}
+
+// withFieldViolations returns s with a BadRequest detail listing violations,
+// or s itself if there are none.
+func withFieldViolations(s *status.Status, violations []FieldViolation) *status.Status {
+	if len(violations) == 0 {
+		return s
+	}
+	br := &errdetails.BadRequest{}
+	for _, v := range violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	ds, err := s.WithDetails(br)
+	if err != nil {
+		return s
+	}
+	return ds
+}
@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// fingerprintFrames is the number of stack frames hashed by Fingerprint.
+var fingerprintFrames = 3
+
+// maxFingerprintCauses bounds the number of causes hashed by Fingerprint.
+const maxFingerprintCauses = 32
+
+// SetFingerprintFrames sets the number of stack frames hashed by Fingerprint.
+// The default is 3.
+func SetFingerprintFrames(n int) { fingerprintFrames = n }
+
+// Fingerprint returns a stable hash of err for grouping occurrences of the
+// same error, or "" if err is nil. The hash covers:
+//
+//   - the kind and machine-readable reason of err
+//   - the function names of the top stack frames of err outside this package
+//     (see SetFingerprintFrames)
+//   - the kinds of the causes of err, depth first
+//
+// Messages, file names and line numbers are not hashed, so errors whose
+// messages embed IDs, or raised by code that has moved, share a fingerprint.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	parts := []string{
+		codeLabel(Kind(err)),
+		reasonOf(err),
+		strings.Join(fingerprintFunctions(err), ","),
+		strings.Join(causeKinds(err, nil), ","),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// fingerprintFunctions returns the function names of the top stack frames of
+// err outside this package and the Go runtime.
+func fingerprintFunctions(err error) []string {
+	var stackErr interface{ GetStack() stack }
+	if !errors.As(err, &stackErr) {
+		return nil
+	}
+	var functions []string
+	for _, f := range stackErr.GetStack() {
+		if len(functions) >= fingerprintFrames {
+			break
+		}
+		if strings.HasPrefix(f.function, "runtime.") ||
+			(strings.HasPrefix(f.function, libraryPrefix) && !strings.HasSuffix(f.file, "_test.go")) {
+			continue
+		}
+		functions = append(functions, f.function)
+	}
+	return functions
+}
+
+// causeKinds appends the kinds of the causes of err, depth first, to kinds.
+func causeKinds(err error, kinds []string) []string {
+	for _, cause := range causes(err) {
+		if len(kinds) >= maxFingerprintCauses {
+			break
+		}
+		kinds = append(kinds, codeLabel(Kind(cause)))
+		kinds = causeKinds(cause, kinds)
+	}
+	return kinds
+}
+
+// causes returns the errors directly causing err: the errors it joins, or its
+// cause, or the error it wraps.
+func causes(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if causeErr, ok := err.(interface{ GetCause() error }); ok {
+		cause := causeErr.GetCause()
+		if joined, ok := cause.(interface{ Unwrap() []error }); ok {
+			return joined.Unwrap()
+		}
+		if cause != nil {
+			return []error{cause}
+		}
+		return nil
+	}
+	if cause := errors.Unwrap(err); cause != nil {
+		return []error{cause}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+func newStationNotFound(id string) error {
+	return NewNotFoundError(fmt.Sprintf("station %s", id))
+}
+
+func lookupStation(id string) error {
+	return NewNotFoundError(fmt.Sprintf("station %s", id))
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, "", Fingerprint(nil))
+
+	fp := Fingerprint(newStationNotFound("KDEN"))
+	assert.Equal(t, 16, len(fp))
+
+	// messages are not hashed
+	assert.Equal(t, fp, Fingerprint(newStationNotFound("KBOS")))
+
+	// nor are line numbers
+	err1 := NewNotFoundError("foo")
+	err2 := NewNotFoundError("bar")
+	assert.Equal(t, Fingerprint(err1), Fingerprint(err2))
+
+	// the calling functions are
+	assert.NotEqual(t, fp, Fingerprint(lookupStation("KDEN")))
+	assert.NotEqual(t, fp, Fingerprint(err1))
+
+	// as is the kind
+	assert.NotEqual(t, Fingerprint(NewNotFoundError("foo")), Fingerprint(NewInternalError("foo")))
+	assert.Equal(t, Fingerprint(errors.New("foo")), Fingerprint(errors.New("bar")))
+	assert.NotEqual(t, Fingerprint(errors.New("foo")), Fingerprint(context.Canceled))
+}
+
+func TestFingerprintReason(t *testing.T) {
+	fromCause := func(cause error) error {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(cause)
+		return FromContext(ctx)
+	}
+	assert.Equal(t, Fingerprint(fromCause(ErrServerShutdown)), Fingerprint(fromCause(ErrServerShutdown)))
+	assert.NotEqual(t, Fingerprint(fromCause(ErrServerShutdown)), Fingerprint(fromCause(ErrClientDisconnected)))
+}
+
+func TestFingerprintCauses(t *testing.T) {
+	withCause := func(cause ...error) error { return NewInternalError("foo", cause...) }
+
+	fp := Fingerprint(withCause(NewUnavailableError("bar")))
+	assert.Equal(t, fp, Fingerprint(withCause(NewUnavailableError("baz"))))
+	assert.NotEqual(t, fp, Fingerprint(withCause()))
+	assert.NotEqual(t, fp, Fingerprint(withCause(NewNotFoundError("bar"))))
+	assert.NotEqual(t, fp, Fingerprint(withCause(NewUnavailableError("bar"), NewUnavailableError("bar"))))
+	assert.NotEqual(t, fp, Fingerprint(withCause(fmt.Errorf("bar: %w", context.Canceled))))
+
+	assert.Equal(t,
+		[]string{"UNAVAILABLE", "NOT FOUND", "CANCELED", "CANCELED"},
+		causeKinds(withCause(NewUnavailableError("bar", NewNotFoundError("baz")), fmt.Errorf("bar: %w", context.Canceled)), nil))
+	assert.Equal(t,
+		[]string{"INTERNAL ERROR", "UNAVAILABLE"},
+		causeKinds(NewErrors(NewInternalError("foo"), NewUnavailableError("bar")), nil))
+}
+
+func TestFingerprintFrames(t *testing.T) {
+	defer SetFingerprintFrames(3)
+
+	var err error = NewNotFoundError("foo")
+	assert.Equal(t, []string{
+		"github.com/weathersource/go-errors.TestFingerprintFrames",
+		"testing.tRunner",
+	}, fingerprintFunctions(err))
+
+	// frames within this package are skipped
+	err = NewPassthroughError("foo", errors.New("bar"))
+	assert.Equal(t, "github.com/weathersource/go-errors.TestFingerprintFrames", fingerprintFunctions(err)[0])
+
+	SetFingerprintFrames(1)
+	assert.Equal(t, []string{"github.com/weathersource/go-errors.TestFingerprintFrames"}, fingerprintFunctions(err))
+	assert.Nil(t, fingerprintFunctions(errors.New("foo")))
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	codes "google.golang.org/grpc/codes"
+)
+
+// FSClassifier classifies filesystem errors, such as those returned by os.Open
+// and os.Mkdir:
+//
+//	fs.ErrNotExist          NOT_FOUND
+//	fs.ErrExist             ALREADY_EXISTS
+//	fs.ErrPermission        PERMISSION_DENIED
+//	os.ErrDeadlineExceeded  DEADLINE_EXCEEDED
+//	fs.ErrClosed            FAILED_PRECONDITION
+//
+// The operation and path of any *fs.PathError or *os.LinkError are recorded in
+// the metadata of errors returned by NewPassthroughError.
+var FSClassifier Classifier = ClassifierFunc(classifyFS)
+
+// classifyFS implements FSClassifier.
+func classifyFS(err error) codes.Code {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return codes.NotFound
+	case errors.Is(err, fs.ErrExist):
+		return codes.AlreadyExists
+	case errors.Is(err, fs.ErrPermission):
+		return codes.PermissionDenied
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, fs.ErrClosed):
+		return codes.FailedPrecondition
+	}
+	return codes.OK
+}
+
+// describeFS records the operation and path of filesystem errors in info.
+func describeFS(err error, info *errorInfo) {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		info.metadata["op"] = pathErr.Op
+		info.metadata["path"] = pathErr.Path
+	}
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		info.metadata["op"] = linkErr.Op
+		info.metadata["old"] = linkErr.Old
+		info.metadata["new"] = linkErr.New
+	}
+}
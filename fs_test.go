@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+)
+
+func TestFSClassifier(t *testing.T) {
+	dir := t.TempDir()
+
+	_, errNotExist := os.Open(filepath.Join(dir, "missing"))
+	errExist := os.Mkdir(dir, 0o755)
+
+	f, err := os.Create(filepath.Join(dir, "file"))
+	assert.Nil(t, err)
+	f.Close()
+	_, errClosed := f.Write([]byte("foo"))
+
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{errNotExist, codes.NotFound},
+		{errExist, codes.AlreadyExists},
+		{&fs.PathError{Op: "open", Path: "/etc/shadow", Err: fs.ErrPermission}, codes.PermissionDenied},
+		{fmt.Errorf("read: %w", os.ErrDeadlineExceeded), codes.DeadlineExceeded},
+		{errClosed, codes.FailedPrecondition},
+		{errors.New("foo"), codes.OK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, FSClassifier.Classify(test.err))
+	}
+}
+
+func TestPassthroughFSMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	_, err := os.Open(path)
+
+	res := ClientPassthroughPolicy.NewPassthroughError("foo", fmt.Errorf("loading: %w", err))
+	notFound, ok := res.(*NotFoundError)
+	if assert.True(t, ok) {
+		assert.Equal(t, "NOT FOUND. foo", notFound.GetMessage())
+		assert.Equal(t, map[string]string{"op": "open", "path": path}, notFound.GetMetadata())
+	}
+
+	// masked errors keep their metadata
+	res = NewPassthroughError("foo", err)
+	internal, ok := res.(*InternalError)
+	if assert.True(t, ok) {
+		assert.Equal(t, "open", internal.GetMetadata()["op"])
+	}
+
+	err = os.Rename(path, path+".new")
+	res = ClientPassthroughPolicy.NewPassthroughError("foo", err)
+	assert.Equal(t,
+		map[string]string{"op": "rename", "old": path, "new": path + ".new"},
+		res.(*NotFoundError).GetMetadata())
+
+	// errors without metadata
+	res = NewPassthroughError("foo", errors.New("bar"))
+	assert.Nil(t, res.(*InternalError).GetMetadata())
+}
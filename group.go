@@ -0,0 +1,154 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Group is a collection of goroutines working on subtasks that are part of
+// the same overall task. Unlike golang.org/x/sync/errgroup, a Group collects
+// every failure rather than only the first one.
+//
+// A zero Group is valid, has no limit on the number of active goroutines,
+// and does not cancel on error.
+type Group struct {
+	cancel   context.CancelFunc
+	cancelOn func(error) bool
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	mu   sync.Mutex
+	next int
+	errs []groupError
+}
+
+// groupError is an error returned by the goroutine started by the n-th call
+// to Go.
+type groupError struct {
+	n   int
+	err error
+}
+
+// NewGroup returns a new Group and an associated Context derived from ctx.
+//
+// The derived Context is canceled the first time a function passed to Go
+// returns an error accepted by the predicate set with SetCancelOn, or the
+// first time Wait returns, whichever occurs first.
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of active goroutines in this group to at most n.
+// A negative value indicates no limit.
+//
+// Any subsequent call to the Go method will block until it can add an active
+// goroutine without exceeding the configured limit.
+//
+// The limit must not be modified while any goroutines in the group are active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("errors: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// SetCancelOn sets the predicate used to decide whether an error returned by
+// a function passed to Go cancels the Context returned by NewGroup. A nil
+// predicate, the default, never cancels. To cancel on any error, pass a
+// predicate that always returns true; to cancel only on errors that are not
+// recoverable, pass something like
+//
+//	func(err error) bool {
+//		return errors.Retryable(err, errors.RetryOptions{Idempotent: true}).Action == errors.NoRetry
+//	}
+//
+// SetCancelOn must not be called while any goroutines in the group are active.
+func (g *Group) SetCancelOn(pred func(error) bool) {
+	g.cancelOn = pred
+}
+
+// Go calls the given function in a new goroutine. It blocks until the new
+// goroutine can be added without the number of active goroutines in the group
+// exceeding the configured limit.
+//
+// A non-nil error returned by f is collected and reported by Wait. If f
+// panics, the panic is recovered and collected as an InternalError.
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	n := g.next
+	g.next++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = NewInternalError(fmt.Sprintf("panic: %v", r))
+				}
+			}()
+			err = f()
+		}()
+
+		if err != nil {
+			g.fail(n, err)
+		}
+	}()
+}
+
+// Wait blocks until all function calls from the Go method have returned, then
+// returns every error they produced, in the order the functions were passed
+// to Go. If no function failed, Wait returns nil.
+func (g *Group) Wait() *Errors {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	sort.Slice(g.errs, func(i, j int) bool { return g.errs[i].n < g.errs[j].n })
+	errs := make([]error, len(g.errs))
+	for i, e := range g.errs {
+		errs[i] = e.err
+	}
+	return NewErrors(errs...)
+}
+
+// done releases the resources held by a goroutine started by Go.
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+// fail records err as the result of the n-th call to Go, canceling the group
+// if the cancel predicate accepts it.
+func (g *Group) fail(n int, err error) {
+	g.mu.Lock()
+	g.errs = append(g.errs, groupError{n: n, err: err})
+	g.mu.Unlock()
+
+	if g.cancel != nil && g.cancelOn != nil && g.cancelOn(err) {
+		g.cancel()
+	}
+}
@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+func TestGroupWait(t *testing.T) {
+	var g Group
+	assert.Nil(t, g.Wait())
+
+	for i := 0; i < 10; i++ {
+		i := i
+		g.Go(func() error {
+			// finish in reverse order to exercise ordering
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			if i%3 == 0 {
+				return NewNotFoundError(string(rune('a' + i)))
+			}
+			return nil
+		})
+	}
+	errs := g.Wait()
+	if assert.NotNil(t, errs) {
+		snap := errs.Snapshot()
+		assert.Equal(t, 4, len(snap))
+		for i, msg := range []string{"a", "d", "g", "j"} {
+			assert.Equal(t, "NOT FOUND. "+msg, snap[i].(*NotFoundError).GetMessage())
+		}
+	}
+}
+
+func TestGroupPanic(t *testing.T) {
+	var g Group
+	g.Go(func() error { panic("boom") })
+	g.Go(func() error { return nil })
+
+	errs := g.Wait()
+	if assert.NotNil(t, errs) {
+		assert.Equal(t, 1, errs.Len())
+		err, ok := errs.Snapshot()[0].(*InternalError)
+		if assert.True(t, ok) {
+			assert.Equal(t, "INTERNAL ERROR. panic: boom", err.GetMessage())
+			assert.NotEmpty(t, err.GetStack())
+		}
+	}
+}
+
+func TestGroupSetLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+
+	var active, max int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&active, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			return nil
+		})
+	}
+	assert.Nil(t, g.Wait())
+	assert.True(t, max <= 2)
+
+	g.SetLimit(-1)
+	assert.Nil(t, g.sem)
+}
+
+func TestGroupSetCancelOn(t *testing.T) {
+	permanent := func(err error) bool {
+		tmp, ok := err.(interface{ Temporary() bool })
+		return !ok || !tmp.Temporary()
+	}
+
+	// a temporary error doesn't cancel the group
+	g, ctx := NewGroup(context.Background())
+	g.SetCancelOn(permanent)
+	g.Go(func() error { return NewUnavailableError("foo") })
+	g.wg.Wait()
+	assert.Nil(t, ctx.Err())
+	assert.Equal(t, 1, g.Wait().Len())
+	assert.Equal(t, context.Canceled, ctx.Err())
+
+	// a permanent error does
+	g, ctx = NewGroup(context.Background())
+	g.SetCancelOn(permanent)
+	g.Go(func() error { return NewInvalidArgumentError("foo") })
+	g.Go(func() error {
+		<-ctx.Done()
+		return NewCanceledError("bar", ctx.Err())
+	})
+	errs := g.Wait()
+	if assert.NotNil(t, errs) {
+		snap := errs.Snapshot()
+		assert.Equal(t, 2, len(snap))
+		assert.IsType(t, &InvalidArgumentError{}, snap[0])
+		assert.IsType(t, &CanceledError{}, snap[1])
+	}
+
+	// without a predicate, errors never cancel
+	g, ctx = NewGroup(context.Background())
+	g.Go(func() error { return errors.New("foo") })
+	g.wg.Wait()
+	assert.Nil(t, ctx.Err())
+	g.Wait()
+}
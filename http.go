@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	codes "google.golang.org/grpc/codes"
+)
+
+// HTTPClassifier classifies errors carrying the HTTP status of an upstream
+// response by that status (see HTTPStatusCode). An error carries a status if
+// it, or an error it wraps, implements
+//
+//	StatusCode() int
+//	HTTPStatusCode() int
+//	Response() *http.Response
+//
+// including those wrapped in a *url.Error.
+//
+// The status is recorded in the metadata of errors returned by
+// NewPassthroughError.
+var HTTPClassifier Classifier = ClassifierFunc(classifyHTTP)
+
+// HTTPStatusCode maps the HTTP status of an upstream response onto the gRPC
+// code identifying the correlating type from this package, or codes.OK if it
+// has no mapping:
+//
+//	400                  INVALID_ARGUMENT
+//	401                  UNAUTHENTICATED
+//	403                  PERMISSION_DENIED
+//	404, 410             NOT_FOUND
+//	408                  DEADLINE_EXCEEDED
+//	409                  ABORTED
+//	412                  FAILED_PRECONDITION
+//	416                  OUT_OF_RANGE
+//	429                  RESOURCE_EXHAUSTED
+//	499                  CANCELED
+//	other 4xx            INVALID_ARGUMENT
+//	500                  INTERNAL
+//	501                  UNIMPLEMENTED
+//	502, 503             UNAVAILABLE
+//	504                  DEADLINE_EXCEEDED
+//	other 5xx            UNKNOWN
+func HTTPStatusCode(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return codes.NotFound
+	case http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return codes.OutOfRange
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusInternalServerError:
+		return codes.Internal
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return codes.InvalidArgument
+	case status >= 500 && status < 600:
+		return codes.Unknown
+	}
+	return codes.OK
+}
+
+// classifyHTTP implements HTTPClassifier.
+func classifyHTTP(err error) codes.Code {
+	status, ok := upstreamStatus(err)
+	if !ok {
+		return codes.OK
+	}
+	return HTTPStatusCode(status)
+}
+
+// describeHTTP records the HTTP status of upstream responses in info.
+func describeHTTP(err error, info *errorInfo) {
+	if status, ok := upstreamStatus(err); ok {
+		info.metadata["http_status"] = strconv.Itoa(status)
+	}
+}
+
+// upstreamStatus returns the HTTP status of the upstream response carried by
+// err, if any.
+func upstreamStatus(err error) (int, bool) {
+	var statusErr interface{ StatusCode() int }
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode(), true
+	}
+	var httpStatusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpStatusErr) {
+		return httpStatusErr.HTTPStatusCode(), true
+	}
+	var respErr interface{ Response() *http.Response }
+	if errors.As(err, &respErr) {
+		if resp := respErr.Response(); resp != nil {
+			return resp.StatusCode, true
+		}
+	}
+	return 0, false
+}
+
+// isClientStatus reports whether status is an HTTP 4xx status.
+func isClientStatus(status int) bool { return status >= 400 && status < 500 }
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+)
+
+type statusCodeError struct{ status int }
+
+func (e statusCodeError) Error() string   { return http.StatusText(e.status) }
+func (e statusCodeError) StatusCode() int { return e.status }
+
+type httpStatusCodeError struct{ status int }
+
+func (e httpStatusCodeError) Error() string       { return http.StatusText(e.status) }
+func (e httpStatusCodeError) HTTPStatusCode() int { return e.status }
+
+type responseError struct{ resp *http.Response }
+
+func (e responseError) Error() string            { return e.resp.Status }
+func (e responseError) Response() *http.Response { return e.resp }
+
+func TestHTTPStatusCode(t *testing.T) {
+	tests := []struct {
+		status int
+		code   codes.Code
+	}{
+		{200, codes.OK},
+		{304, codes.OK},
+		{400, codes.InvalidArgument},
+		{401, codes.Unauthenticated},
+		{403, codes.PermissionDenied},
+		{404, codes.NotFound},
+		{410, codes.NotFound},
+		{408, codes.DeadlineExceeded},
+		{409, codes.Aborted},
+		{412, codes.FailedPrecondition},
+		{416, codes.OutOfRange},
+		{422, codes.InvalidArgument},
+		{429, codes.ResourceExhausted},
+		{499, codes.Canceled},
+		{500, codes.Internal},
+		{501, codes.Unimplemented},
+		{502, codes.Unavailable},
+		{503, codes.Unavailable},
+		{504, codes.DeadlineExceeded},
+		{507, codes.Unknown},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, HTTPStatusCode(test.status), test.status)
+	}
+}
+
+func TestHTTPClassifier(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{statusCodeError{404}, codes.NotFound},
+		{fmt.Errorf("partner: %w", statusCodeError{429}), codes.ResourceExhausted},
+		{httpStatusCodeError{409}, codes.Aborted},
+		{responseError{&http.Response{Status: "503 Service Unavailable", StatusCode: 503}}, codes.Unavailable},
+		{&url.Error{Op: "Get", URL: "https://api.example.com/stations", Err: statusCodeError{404}}, codes.NotFound},
+		{responseError{&http.Response{Status: "200 OK", StatusCode: 200}}, codes.OK},
+		{errors.New("foo"), codes.OK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, HTTPClassifier.Classify(test.err))
+	}
+}
+
+func TestPassthroughHTTP(t *testing.T) {
+	err := &url.Error{Op: "Get", URL: "https://api.example.com/stations", Err: statusCodeError{404}}
+
+	res := ClientPassthroughPolicy.NewPassthroughError("foo", err)
+	notFound, ok := res.(*NotFoundError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 404, notFound.GetCode())
+		assert.Equal(t, "404", notFound.GetMetadata()["http_status"])
+		assert.Equal(t, "https://api.example.com/stations", notFound.GetMetadata()["url"])
+	}
+
+	// by default, upstream client errors are masked as bad gateway errors
+	res = NewPassthroughError("foo", err)
+	internal, ok := res.(*InternalError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 502, internal.GetCode())
+		assert.Equal(t, codes.Internal, internal.GRPCStatus().Code())
+	}
+
+	// as are upstream server errors that are not exposed
+	res = NewPassthroughError("foo", statusCodeError{500})
+	assert.Equal(t, 502, res.(*InternalError).GetCode())
+
+	// other masked errors are not
+	res = NewPassthroughError("foo", errors.New("bar"))
+	assert.Equal(t, 500, res.(*InternalError).GetCode())
+
+	// exposed upstream server errors are passed through
+	res = NewPassthroughError("foo", statusCodeError{503})
+	assert.IsType(t, &UnavailableError{}, res)
+}
+
+func TestPassthroughPolicyUpstream4xx(t *testing.T) {
+	err := statusCodeError{429}
+
+	res := DefaultPassthroughPolicy.SurfaceUpstream4xx().NewPassthroughError("foo", err)
+	assert.IsType(t, &ResourceExhaustedError{}, res)
+
+	res = ClientPassthroughPolicy.MaskUpstream4xx().NewPassthroughError("foo", err)
+	internal, ok := res.(*InternalError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 502, internal.GetCode())
+	}
+
+	// other errors follow the policy
+	res = DefaultPassthroughPolicy.SurfaceUpstream4xx().NewPassthroughError("foo", NewNotFoundError("bar"))
+	assert.IsType(t, &InternalError{}, res)
+	res = ClientPassthroughPolicy.MaskUpstream4xx().NewPassthroughError("foo", NewNotFoundError("bar"))
+	assert.IsType(t, &NotFoundError{}, res)
+	res = ClientPassthroughPolicy.MaskUpstream4xx().NewPassthroughError("foo", statusCodeError{503})
+	assert.IsType(t, &UnavailableError{}, res)
+
+	// the original policy is unchanged
+	assert.IsType(t, &InternalError{}, DefaultPassthroughPolicy.NewPassthroughError("foo", err))
+}
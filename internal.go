@@ -35,6 +35,8 @@ type InternalError struct {
 	logMessage string
 	cause      error
 	stack      stack
+	reason     string
+	metadata   map[string]string
 	rpcCode    codes.Code
 }
 
@@ -44,7 +46,7 @@ func NewInternalError(Message string, cause ...error) *InternalError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &InternalError{
+	e := &InternalError{
 		Code:       500,
 		Message:    "INTERNAL ERROR.",
 		logMessage: Message,
@@ -52,6 +54,8 @@ func NewInternalError(Message string, cause ...error) *InternalError {
 		stack:      getTrace(),
 		rpcCode:    codes.Internal,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -61,6 +65,9 @@ func (e *InternalError) Error() string { return errorStr(e) }
 func (e *InternalError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *InternalError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -75,6 +82,18 @@ func (e *InternalError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *InternalError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *InternalError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *InternalError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *InternalError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *InternalError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *InternalError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
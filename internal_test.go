@@ -112,6 +112,24 @@ func TestInternalErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestInternalErrorGetReason(t *testing.T) {
+	for _, test := range InternalErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewInternalError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestInternalErrorGetMetadata(t *testing.T) {
+	for _, test := range InternalErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewInternalError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestInternalErrorJson(t *testing.T) {
 	for _, test := range InternalErrorTests {
 		json, _ := json.Marshal(test.err)
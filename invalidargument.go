@@ -14,15 +14,21 @@ import (
 //
 //		INVALID ARGUMENT. Request field x.y.z is xxx, expected one of [yyy, zzz].
 //
+// Any field violations are marshalled to JSON and returned via GRPC status as
+// a BadRequest detail.
+//
 // HTTP Mapping: 400 BAD REQUEST
 //
 // RPC Mapping: INVALID_ARGUMENT
 type InvalidArgumentError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code            int              `json:"errorCode"`
+	Message         string           `json:"errorMessage"`
+	FieldViolations []FieldViolation `json:"fieldViolations,omitempty"`
+	cause           error
+	stack           stack
+	reason          string
+	metadata        map[string]string
+	rpcCode         codes.Code
 }
 
 // NewInvalidArgumentError returns a new InvalidArgumentError.
@@ -31,13 +37,15 @@ func NewInvalidArgumentError(Message string, cause ...error) *InvalidArgumentErr
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &InvalidArgumentError{
+	e := &InvalidArgumentError{
 		Code:    400,
 		Message: "INVALID ARGUMENT. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.InvalidArgument,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -47,6 +55,9 @@ func (e *InvalidArgumentError) Error() string { return errorStr(e) }
 func (e *InvalidArgumentError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *InvalidArgumentError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -61,7 +72,28 @@ func (e *InvalidArgumentError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *InvalidArgumentError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *InvalidArgumentError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *InvalidArgumentError) setReason(reason string) { e.reason = reason }
+
+// GetFieldViolations returns the field violations associated with this error.
+func (e *InvalidArgumentError) GetFieldViolations() []FieldViolation { return e.FieldViolations }
+
+// AddFieldViolation adds a violation of the request field identified by field
+// to this error.
+func (e *InvalidArgumentError) AddFieldViolation(field, description string) {
+	e.FieldViolations = append(e.FieldViolations, FieldViolation{Field: field, Description: description})
+}
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *InvalidArgumentError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *InvalidArgumentError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *InvalidArgumentError) GRPCStatus() *status.Status {
-	return status.New(e.rpcCode, e.Message)
+	return withFieldViolations(status.New(e.rpcCode, e.Message), e.FieldViolations)
 }
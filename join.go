@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"errors"
+)
+
+// SetFlattenJoined sets whether e flattens joined errors. If flatten is true,
+// Append and Merge replace any error implementing Unwrap() []error, such as
+// those returned by errors.Join, with the errors it wraps, recursively, and any
+// joined errors e already holds are flattened. Note that this discards the
+// message of the joined error itself, which matters for multi-%w errors from
+// fmt.Errorf. To build a flattened Errors from a single error, use FromJoined.
+func (e *Errors) SetFlattenJoined(flatten bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flattenJoined = flatten
+	if !flatten {
+		return
+	}
+	errs := flattenAll(e.errs)
+	e.errs = make([]error, 0, len(errs))
+	e.seen = nil
+	for _, err := range errs {
+		if err == nil {
+			// retain nils passed to NewErrors so that indexes are preserved
+			e.errs = append(e.errs, err)
+			continue
+		}
+		e.retain(err)
+	}
+}
+
+// Unwrap returns the non-nil errors in e, in order, so that errors.Is and
+// errors.As consider every error in e.
+func (e *Errors) Unwrap() []error {
+	errs := e.Snapshot()
+	out := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			out = append(out, err)
+		}
+	}
+	return out
+}
+
+// Unwrap returns the errors in e, in key order, so that errors.Is and
+// errors.As consider every error in e.
+func (e *KeyedErrors) Unwrap() []error {
+	_, errs := e.snapshot()
+	return errs
+}
+
+// Joined returns the errors in e joined with errors.Join, in order. Like
+// errors.Join, nil errors are discarded and Joined returns nil if e holds no
+// errors.
+func (e *Errors) Joined() error {
+	return errors.Join(e.Snapshot()...)
+}
+
+// FromJoined returns the errors joined in err, in order, as an Errors. Joined
+// errors, that is errors implementing Unwrap() []error, are flattened
+// recursively and nil errors are discarded. FromJoined returns nil if err is
+// nil.
+func FromJoined(err error) *Errors {
+	if err == nil {
+		return nil
+	}
+	return &Errors{errs: flatten(nil, err)}
+}
+
+// flatten appends err to errs, replacing joined errors by the errors they
+// join, recursively. Nil errors are discarded.
+func flatten(errs []error, err error) []error {
+	if err == nil {
+		return errs
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return append(errs, err)
+	}
+	for _, err := range joined.Unwrap() {
+		errs = flatten(errs, err)
+	}
+	return errs
+}
+
+// flattenAll returns errs with joined errors flattened.
+func flattenAll(errs []error) []error {
+	var out []error
+	for _, err := range errs {
+		if err == nil {
+			// preserve nils, which NewErrors retains
+			out = append(out, err)
+			continue
+		}
+		out = flatten(out, err)
+	}
+	return out
+}
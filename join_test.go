@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+func TestErrorsUnwrap(t *testing.T) {
+	notFound := NewNotFoundError("foo")
+	errs := NewErrors(errors.New("bar"), nil, fmt.Errorf("baz: %w", context.Canceled), notFound)
+	assert.Equal(t, 3, len(errs.Unwrap()))
+
+	assert.True(t, errors.Is(errs, context.Canceled))
+	assert.True(t, errors.Is(errs, notFound))
+	assert.False(t, errors.Is(errs, context.DeadlineExceeded))
+
+	var target *NotFoundError
+	assert.True(t, errors.As(errs, &target))
+	assert.Equal(t, notFound, target)
+
+	var nilErrs *Errors
+	assert.Empty(t, nilErrs.Unwrap())
+}
+
+func TestKeyedErrorsUnwrap(t *testing.T) {
+	e := NewKeyedErrors()
+	e.Set("KDEN", NewNotFoundError("foo"))
+	e.Set("KBOS", context.Canceled)
+	assert.True(t, errors.Is(e, context.Canceled))
+	var target *NotFoundError
+	assert.True(t, errors.As(e, &target))
+}
+
+func TestErrorsJoined(t *testing.T) {
+	assert.Nil(t, NewErrors().Joined())
+	assert.Nil(t, NewErrors(nil).Joined())
+
+	foo, bar := errors.New("foo"), errors.New("bar")
+	joined := NewErrors(foo, nil, bar).Joined()
+	assert.Equal(t, "foo\nbar", joined.Error())
+	assert.Equal(t, []error{foo, bar}, joined.(interface{ Unwrap() []error }).Unwrap())
+}
+
+func TestFromJoined(t *testing.T) {
+	assert.Nil(t, FromJoined(nil))
+
+	foo, bar, baz := errors.New("foo"), errors.New("bar"), errors.New("baz")
+	errs := FromJoined(errors.Join(errors.Join(foo, nil, bar), baz))
+	assert.Equal(t, []error{foo, bar, baz}, errs.Snapshot())
+
+	// wrapped errors are kept whole
+	wrapped := fmt.Errorf("bat: %w", foo)
+	assert.Equal(t, []error{wrapped}, FromJoined(wrapped).Snapshot())
+
+	// round trip
+	assert.Equal(t, []error{foo, bar, baz}, FromJoined(errs.Joined()).Snapshot())
+}
+
+func TestErrorsSetFlattenJoined(t *testing.T) {
+	foo, bar, baz := errors.New("foo"), errors.New("bar"), errors.New("baz")
+
+	errs := NewErrors(errors.Join(foo, bar), nil, baz)
+	assert.Equal(t, 3, len(errs.Snapshot()))
+
+	// errors already held are flattened
+	errs.SetFlattenJoined(true)
+	assert.Equal(t, []error{foo, bar, nil, baz}, errs.Snapshot())
+
+	errs = NewErrors()
+	errs.SetFlattenJoined(true)
+	errs.Append(errors.Join(foo, errors.Join(bar, baz)))
+	assert.Equal(t, []error{foo, bar, baz}, errs.Snapshot())
+
+	errs = NewErrors()
+	errs.SetFlattenJoined(true)
+	errs.Merge(NewErrors(errors.Join(foo, bar)))
+	assert.Equal(t, []error{foo, bar}, errs.Snapshot())
+
+	// other Errors are unaffected
+	errs = NewErrors()
+	errs.Append(errors.Join(foo, bar))
+	assert.Equal(t, 1, errs.Len())
+
+	// flattening respects the limits of e
+	errs = NewErrors(errors.Join(foo, bar, foo))
+	errs.SetDedup(true)
+	errs.SetMaxLen(1)
+	errs.SetFlattenJoined(true)
+	assert.Equal(t, []error{foo}, errs.Snapshot())
+	assert.Equal(t, 2, errs.Omitted())
+}
@@ -0,0 +1,308 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	code "google.golang.org/genproto/googleapis/rpc/code"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// ErrorInfo metadata keys used in the gRPC encoding of KeyedErrors
+const (
+	keyedErrorKey     = "key"
+	keyedErrorMessage = "message"
+)
+
+// KeyedErrors is a container for errors keyed by item, such as the index or
+// ID of each failed item of a batch request. It implements the error
+// interface and is safe for concurrent use by multiple goroutines.
+//
+// Keys are iterated in the order they were first set.
+//
+// JSON Mapping:
+//
+//	{"errors":{"KDEN":{"errorCode":404,"errorMessage":"NOT FOUND. ..."}}}
+//
+// HTTP Mapping: the aggregated code of all items, or 207 MULTI-STATUS
+//
+// RPC Mapping: the aggregated code of all items, with one ErrorInfo detail
+// per item
+type KeyedErrors struct {
+	mu   sync.RWMutex
+	keys []string
+	errs map[string]error
+}
+
+// NewKeyedErrors returns an empty KeyedErrors.
+func NewKeyedErrors() *KeyedErrors {
+	return &KeyedErrors{errs: make(map[string]error)}
+}
+
+// Set records err as the error for key, replacing any error previously set
+// for key. Setting a nil error removes key.
+func (e *KeyedErrors) Set(key string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.errs == nil {
+		e.errs = make(map[string]error)
+	}
+	_, found := e.errs[key]
+	switch {
+	case err == nil && found:
+		delete(e.errs, key)
+		for i, k := range e.keys {
+			if k == key {
+				e.keys = append(e.keys[:i:i], e.keys[i+1:]...)
+				break
+			}
+		}
+	case err != nil:
+		if !found {
+			e.keys = append(e.keys, key)
+		}
+		e.errs[key] = err
+	}
+}
+
+// Get returns the error for key, or nil if there is none.
+func (e *KeyedErrors) Get(key string) error {
+	if e == nil {
+		return nil
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.errs[key]
+}
+
+// Len returns the number of keys in e.
+func (e *KeyedErrors) Len() int {
+	if e == nil {
+		return 0
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.keys)
+}
+
+// Keys returns the keys of e in order.
+func (e *KeyedErrors) Keys() []string {
+	keys, _ := e.snapshot()
+	return keys
+}
+
+// Range calls f for each key and error in e, in order, until f returns false.
+// Range iterates over a snapshot of e, so f may safely modify e.
+func (e *KeyedErrors) Range(f func(key string, err error) bool) {
+	keys, errs := e.snapshot()
+	for i, key := range keys {
+		if !f(key, errs[i]) {
+			return
+		}
+	}
+}
+
+// Error implements the error interface
+func (e *KeyedErrors) Error() string {
+	keys, errs := e.snapshot()
+	if len(keys) == 0 {
+		return ""
+	} else if len(keys) == 1 {
+		return fmt.Sprintf("%s: %s", keys[0], errs[0].Error())
+	}
+	logWithKey := make([]string, len(keys))
+	for i, key := range keys {
+		logWithKey[i] = fmt.Sprintf("%s: %s", key, errs[i].Error())
+	}
+	return fmt.Sprintf("MULTIPLE ERRORS.\n%s", strings.Join(logWithKey, "\n"))
+}
+
+// Timeout indicates if this error is the result of a timeout.
+func (e *KeyedErrors) Timeout() bool { return e.errors().Timeout() }
+
+// Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
+func (e *KeyedErrors) Temporary() bool { return e.errors().Temporary() }
+
+// GetCode returns the HTTP status code associated with this error.
+func (e *KeyedErrors) GetCode() int { return e.errors().GetCode() }
+
+// GetMessage returns the message associated with this error.
+func (e *KeyedErrors) GetMessage() string { return e.errors().GetMessage() }
+
+// GetCause returns any causal errors associated with this error.
+func (e *KeyedErrors) GetCause() error { return e.errors().GetCause() }
+
+// GetStack returns the trace stack associated with this error.
+func (e *KeyedErrors) GetStack() stack { return e.errors().GetStack() }
+
+// GRPCStatus implements an interface required to return proper GRPC status
+// codes. The code is chosen by the global aggregation policy (see
+// SetAggregationPolicy) and each item is attached as an ErrorInfo detail whose
+// reason is the item's code and whose metadata holds the item's key and
+// message. Like the details, the message of the status is built from the
+// status messages of the items, so it holds none of their log messages.
+func (e *KeyedErrors) GRPCStatus() *status.Status {
+	keys, errs := e.snapshot()
+	if len(keys) == 0 {
+		return nil
+	}
+	itemStatuses := make([]*status.Status, len(errs))
+	msgWithKey := make([]string, len(keys))
+	for i, key := range keys {
+		itemStatuses[i] = status.Convert(errs[i])
+		msgWithKey[i] = fmt.Sprintf("%s: %s", key, itemStatuses[i].Message())
+	}
+	msg := msgWithKey[0]
+	if len(keys) > 1 {
+		msg = fmt.Sprintf("MULTIPLE ERRORS.\n%s", strings.Join(msgWithKey, "\n"))
+	}
+
+	c, _ := aggregate(errs, nil)
+	s := status.New(c, msg)
+	if c == codes.OK {
+		return s
+	}
+	for i, key := range keys {
+		itemStatus := itemStatuses[i]
+		info := &errdetails.ErrorInfo{
+			Reason: code.Code(itemStatus.Code()).String(),
+			Metadata: map[string]string{
+				keyedErrorKey:     key,
+				keyedErrorMessage: itemStatus.Message(),
+			},
+		}
+		if ds, dErr := s.WithDetails(info); dErr == nil {
+			s = ds
+		}
+	}
+	return s
+}
+
+// KeyedErrorsFromStatus decodes the items of a status produced by
+// KeyedErrors.GRPCStatus. Each item is returned as a gRPC status error. Details
+// that do not describe an item are ignored.
+func KeyedErrorsFromStatus(s *status.Status) *KeyedErrors {
+	e := NewKeyedErrors()
+	for _, d := range s.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+		key, ok := info.GetMetadata()[keyedErrorKey]
+		if !ok {
+			continue
+		}
+		c := codes.Code(code.Code_value[info.GetReason()])
+		e.Set(key, status.Error(c, info.GetMetadata()[keyedErrorMessage]))
+	}
+	return e
+}
+
+// MarshalJSON implements the json.Marshaler interface. Items are marshalled in
+// order. Errors from this package marshal as they do on their own; other
+// errors are marshalled with the HTTP code and message of their gRPC status.
+func (e *KeyedErrors) MarshalJSON() ([]byte, error) {
+	keys, errs := e.snapshot()
+
+	var buffer bytes.Buffer
+	buffer.WriteString(`{"errors":{`)
+	for i, key := range keys {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshalItem(errs[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(k)
+		buffer.WriteString(":")
+		buffer.Write(v)
+	}
+	buffer.WriteString("}}")
+	return buffer.Bytes(), nil
+}
+
+// WriteHTTP writes e to w as a JSON response. If multiStatus is true, the
+// response status is 207 MULTI-STATUS, for use when the other items of the
+// batch succeeded; otherwise it is e.GetCode().
+func (e *KeyedErrors) WriteHTTP(w http.ResponseWriter, multiStatus bool) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	statusCode := e.GetCode()
+	if multiStatus {
+		statusCode = http.StatusMultiStatus
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err = w.Write(body)
+	return err
+}
+
+// errors returns the errors of e, in order, as an Errors.
+func (e *KeyedErrors) errors() *Errors {
+	_, errs := e.snapshot()
+	return NewErrors(errs...)
+}
+
+// snapshot returns copies of the keys of e and their errors, in order.
+func (e *KeyedErrors) snapshot() ([]string, []error) {
+	if e == nil {
+		return nil, nil
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	keys := make([]string, len(e.keys))
+	errs := make([]error, len(e.keys))
+	for i, key := range e.keys {
+		keys[i] = key
+		errs[i] = e.errs[key]
+	}
+	return keys, errs
+}
+
+// marshalItem marshals a single item of KeyedErrors.
+func marshalItem(err error) ([]byte, error) {
+	switch err.(type) {
+	case json.Marshaler,
+		*AbortedError,
+		*AlreadyExistsError,
+		*CanceledError,
+		*DataLossError,
+		*DeadlineExceededError,
+		*FailedPreconditionError,
+		*InternalError,
+		*InvalidArgumentError,
+		*NotFoundError,
+		*NotImplementedError,
+		*OutOfRangeError,
+		*PermissionDeniedError,
+		*ResourceExhaustedError,
+		*UnauthenticatedError,
+		*UnavailableError,
+		*UnknownError:
+		return json.Marshal(err)
+	}
+	s := status.Convert(err)
+	return json.Marshal(struct {
+		Code    int    `json:"errorCode"`
+		Message string `json:"errorMessage"`
+	}{
+		Code:    httpCode(s.Code()),
+		Message: s.Message(),
+	})
+}
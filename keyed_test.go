@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+func TestKeyedErrorsSet(t *testing.T) {
+	e := NewKeyedErrors()
+	e.Set("KDEN", NewNotFoundError("KDEN"))
+	e.Set("KBOS", NewUnavailableError("KBOS"))
+	e.Set("KSEA", nil)
+	e.Set("KORD", errors.New("KORD"))
+	assert.Equal(t, []string{"KDEN", "KBOS", "KORD"}, e.Keys())
+	assert.Equal(t, 3, e.Len())
+
+	// replacing keeps position, removing drops the key
+	e.Set("KDEN", NewInternalError("KDEN"))
+	e.Set("KBOS", nil)
+	assert.Equal(t, []string{"KDEN", "KORD"}, e.Keys())
+	assert.IsType(t, &InternalError{}, e.Get("KDEN"))
+	assert.Nil(t, e.Get("KBOS"))
+
+	var zero KeyedErrors
+	zero.Set("foo", errors.New("foo"))
+	assert.Equal(t, 1, zero.Len())
+
+	var nilErrs *KeyedErrors
+	assert.Equal(t, 0, nilErrs.Len())
+	assert.Nil(t, nilErrs.Get("foo"))
+	assert.Nil(t, nilErrs.Keys())
+}
+
+func TestKeyedErrorsRange(t *testing.T) {
+	e := NewKeyedErrors()
+	e.Set("0", errors.New("foo"))
+	e.Set("1", errors.New("bar"))
+	e.Set("2", errors.New("baz"))
+
+	var keys []string
+	e.Range(func(key string, err error) bool {
+		keys = append(keys, key)
+		e.Set(key, nil)
+		return key != "1"
+	})
+	assert.Equal(t, []string{"0", "1"}, keys)
+	assert.Equal(t, []string{"2"}, e.Keys())
+}
+
+func TestKeyedErrorsError(t *testing.T) {
+	SetVerbosity(Info)
+	e := NewKeyedErrors()
+	assert.Equal(t, "", e.Error())
+	e.Set("KDEN", NewNotFoundError("KDEN"))
+	assert.Equal(t, "KDEN: error 404: NOT FOUND. KDEN", e.Error())
+	e.Set("KBOS", errors.New("foo"))
+	assert.Equal(t, "MULTIPLE ERRORS.\nKDEN: error 404: NOT FOUND. KDEN\nKBOS: foo", e.Error())
+}
+
+func TestKeyedErrorsGetCode(t *testing.T) {
+	e := NewKeyedErrors()
+	assert.Equal(t, 200, e.GetCode())
+	e.Set("KDEN", NewNotFoundError("KDEN"))
+	e.Set("KBOS", NewNotFoundError("KBOS"))
+	assert.Equal(t, 404, e.GetCode())
+	e.Set("KORD", NewUnavailableError("KORD"))
+	assert.Equal(t, 503, e.GetCode())
+	assert.False(t, e.Temporary())
+	assert.False(t, e.Timeout())
+	assert.Equal(t, "MULTIPLE ERRORS.", e.GetMessage())
+	assert.NotNil(t, e.GetCause())
+	assert.Empty(t, e.GetStack())
+}
+
+func TestKeyedErrorsJson(t *testing.T) {
+	e := NewKeyedErrors()
+	e.Set("KDEN", NewNotFoundError("KDEN"))
+	e.Set("KBOS", NewInternalError("secret"))
+	e.Set("KORD", status.Error(codes.Unavailable, "foo"))
+	e.Set("KSEA", NewErrors(errors.New("bar")))
+	j, err := json.Marshal(e)
+	assert.Nil(t, err)
+	assert.Equal(t,
+		`{"errors":{`+
+			`"KDEN":{"errorCode":404,"errorMessage":"NOT FOUND. KDEN"},`+
+			`"KBOS":{"errorCode":500,"errorMessage":"INTERNAL ERROR."},`+
+			`"KORD":{"errorCode":503,"errorMessage":"foo"},`+
+			`"KSEA":{"errorCode":500,"errorMessage":"bar"}}}`,
+		string(j))
+
+	j, err = json.Marshal(NewKeyedErrors())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"errors":{}}`, string(j))
+}
+
+func TestKeyedErrorsWriteHTTP(t *testing.T) {
+	e := NewKeyedErrors()
+	e.Set("KDEN", NewNotFoundError("KDEN"))
+
+	w := httptest.NewRecorder()
+	assert.Nil(t, e.WriteHTTP(w, false))
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"errors":{"KDEN":{"errorCode":404,"errorMessage":"NOT FOUND. KDEN"}}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	assert.Nil(t, e.WriteHTTP(w, true))
+	assert.Equal(t, 207, w.Code)
+}
+
+func TestKeyedErrorsGRPCStatus(t *testing.T) {
+	var nilErrs *KeyedErrors
+	assert.Nil(t, nilErrs.GRPCStatus())
+	assert.Nil(t, NewKeyedErrors().GRPCStatus())
+
+	e := NewKeyedErrors()
+	e.Set("KDEN", NewNotFoundError("KDEN"))
+	e.Set("KBOS", NewAlreadyExistsError("KBOS"))
+	s := e.GRPCStatus()
+	assert.Equal(t, codes.AlreadyExists, s.Code())
+	assert.Equal(t, 2, len(s.Details()))
+
+	d := KeyedErrorsFromStatus(s)
+	assert.Equal(t, []string{"KDEN", "KBOS"}, d.Keys())
+	assert.Equal(t, codes.NotFound, status.Code(d.Get("KDEN")))
+	assert.Equal(t, "NOT FOUND. KDEN", status.Convert(d.Get("KDEN")).Message())
+	assert.Equal(t, codes.AlreadyExists, status.Code(d.Get("KBOS")))
+
+	// log messages are not sent
+	e = NewKeyedErrors()
+	e.Set("KDEN", NewInternalError("db password=hunter2 rejected"))
+	s = e.GRPCStatus()
+	assert.Equal(t, "KDEN: INTERNAL ERROR.", s.Message())
+	assert.NotContains(t, status.Convert(KeyedErrorsFromStatus(s).Get("KDEN")).Message(), "hunter2")
+	e.Set("KBOS", NewNotFoundError("KBOS"))
+	assert.Equal(t, "MULTIPLE ERRORS.\nKDEN: INTERNAL ERROR.\nKBOS: NOT FOUND. KBOS", e.GRPCStatus().Message())
+}
@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	code "google.golang.org/genproto/googleapis/rpc/code"
+	status "google.golang.org/grpc/status"
+)
+
+// maxReasons bounds the number of distinct reasons counted. Errors with other
+// reasons are counted under otherReason.
+const (
+	maxReasons  = 64
+	otherReason = "OTHER"
+)
+
+// metricsDisabled indicates errors are not counted.
+var metricsDisabled atomic.Bool
+
+// counters is a set of counters keyed by label value. If max is positive, at
+// most max distinct labels are counted, any others being counted under
+// otherReason.
+type counters struct {
+	mu     sync.RWMutex
+	max    int
+	counts map[string]*atomic.Int64
+}
+
+// Error counters
+var (
+	kindCounts   = &counters{}
+	codeCounts   = &counters{}
+	reasonCounts = &counters{max: maxReasons}
+)
+
+// add increments the counter for label.
+func (c *counters) add(label string) {
+	c.mu.RLock()
+	n, ok := c.counts[label]
+	c.mu.RUnlock()
+	if !ok {
+		c.mu.Lock()
+		if n, ok = c.counts[label]; !ok {
+			if c.max > 0 && len(c.counts) >= c.max {
+				label = otherReason
+			}
+			if n, ok = c.counts[label]; !ok {
+				if c.counts == nil {
+					c.counts = make(map[string]*atomic.Int64)
+				}
+				n = new(atomic.Int64)
+				c.counts[label] = n
+			}
+		}
+		c.mu.Unlock()
+	}
+	n.Add(1)
+}
+
+// snapshot returns the values of the counters by label.
+func (c *counters) snapshot() map[string]int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	m := make(map[string]int64, len(c.counts))
+	for label, n := range c.counts {
+		m[label] = n.Load()
+	}
+	return m
+}
+
+// SetMetrics switches the counting of errors on or off. Errors are counted
+// by default.
+//
+// Each error created by the constructors of this package is counted by its
+// kind, such as "NotFoundError", and by the name of its gRPC code, such as
+// "NOT_FOUND". Errors returned by NewPassthroughError and FromContext with a
+// machine-readable reason are also counted by reason; at most 64 distinct
+// reasons are counted, any others being counted as "OTHER". The counters are
+// returned by Metrics, and may be served in the Prometheus text format by
+// MetricsHandler.
+func SetMetrics(enabled bool) { metricsDisabled.Store(!enabled) }
+
+// Metrics returns the values of the error counters (see SetMetrics), keyed by
+// "kind", "code" and "reason", then by label. The counters are published with
+// expvar by the metricsvar subpackage, which is kept apart so that importing
+// this package does not register the expvar handler:
+//
+//	expvar.Publish("go-errors", metricsvar.MetricsVar())
+func Metrics() map[string]map[string]int64 {
+	return map[string]map[string]int64{
+		"kind":   kindCounts.snapshot(),
+		"code":   codeCounts.snapshot(),
+		"reason": reasonCounts.snapshot(),
+	}
+}
+
+// countError counts an error created by the constructors of this package.
+func countError(err error) {
+	if metricsDisabled.Load() {
+		return
+	}
+	kindCounts.add(reflect.TypeOf(err).Elem().Name())
+	codeCounts.add(code.Code(status.Code(err)).String())
+}
+
+// countReason counts an error with the machine-readable reason.
+func countReason(reason string) {
+	if reason == "" || metricsDisabled.Load() {
+		return
+	}
+	reasonCounts.add(reason)
+}
+
+// MetricsHandler returns an http.Handler serving the error counters (see
+// SetMetrics) in the Prometheus text exposition format:
+//
+//	# HELP go_errors_kind_total Errors created, by kind.
+//	# TYPE go_errors_kind_total counter
+//	go_errors_kind_total{kind="NotFoundError"} 12
+//	# HELP go_errors_code_total Errors created, by gRPC code.
+//	# TYPE go_errors_code_total counter
+//	go_errors_code_total{code="NOT_FOUND"} 12
+//	# HELP go_errors_reason_total Errors created, by reason.
+//	# TYPE go_errors_reason_total counter
+//	go_errors_reason_total{reason="DNS_NOT_FOUND"} 3
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writeCounter(w, "go_errors_kind_total", "Errors created, by kind.", "kind", kindCounts)
+		writeCounter(w, "go_errors_code_total", "Errors created, by gRPC code.", "code", codeCounts)
+		writeCounter(w, "go_errors_reason_total", "Errors created, by reason.", "reason", reasonCounts)
+	})
+}
+
+// writeCounter writes the counter name, labelled by label, with the values of
+// c to w in the Prometheus text exposition format.
+func writeCounter(w http.ResponseWriter, name, help, label string, c *counters) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	values := c.snapshot()
+	labels := make([]string, 0, len(values))
+	for l := range values {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, labelEscaper.Replace(l), values[l])
+	}
+}
+
+// labelEscaper escapes Prometheus label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
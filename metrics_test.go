@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+// counter returns the value of the counter key of c.
+func counter(c *counters, key string) int64 { return c.snapshot()[key] }
+
+func TestMetrics(t *testing.T) {
+	kinds := counter(kindCounts, "NotFoundError")
+	codes := counter(codeCounts, "NOT_FOUND")
+	NewNotFoundError("foo")
+	NewNotFoundError("bar")
+	assert.Equal(t, kinds+2, counter(kindCounts, "NotFoundError"))
+	assert.Equal(t, codes+2, counter(codeCounts, "NOT_FOUND"))
+
+	kinds = counter(kindCounts, "NotImplementedError")
+	codes = counter(codeCounts, "UNIMPLEMENTED")
+	NewNotImplementedError("foo")
+	assert.Equal(t, kinds+1, counter(kindCounts, "NotImplementedError"))
+	assert.Equal(t, codes+1, counter(codeCounts, "UNIMPLEMENTED"))
+
+	reasons := counter(reasonCounts, ReasonServerShutdown)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrServerShutdown)
+	FromContext(ctx)
+	assert.Equal(t, reasons+1, counter(reasonCounts, ReasonServerShutdown))
+
+	metrics := Metrics()
+	assert.Equal(t, counter(kindCounts, "NotFoundError"), metrics["kind"]["NotFoundError"])
+	assert.Equal(t, counter(codeCounts, "NOT_FOUND"), metrics["code"]["NOT_FOUND"])
+	assert.Equal(t, counter(reasonCounts, ReasonServerShutdown), metrics["reason"][ReasonServerShutdown])
+}
+
+func TestSetMetrics(t *testing.T) {
+	defer SetMetrics(true)
+
+	SetMetrics(false)
+	kinds := counter(kindCounts, "DataLossError")
+	NewDataLossError("foo")
+	countReason(ReasonServerShutdown)
+	assert.Equal(t, kinds, counter(kindCounts, "DataLossError"))
+
+	SetMetrics(true)
+	NewDataLossError("foo")
+	assert.Equal(t, kinds+1, counter(kindCounts, "DataLossError"))
+}
+
+func TestMetricsReasonBound(t *testing.T) {
+	c := &counters{max: maxReasons}
+	for i := 0; i < maxReasons+10; i++ {
+		c.add(fmt.Sprintf("TEST_REASON_%d", i))
+	}
+	c.add("TEST_REASON_0")
+
+	values := c.snapshot()
+	assert.Equal(t, maxReasons+1, len(values))
+	assert.Equal(t, int64(2), values["TEST_REASON_0"])
+	assert.Equal(t, int64(10), values[otherReason])
+}
+
+func TestMetricsHandler(t *testing.T) {
+	NewNotFoundError("foo")
+
+	w := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, body, "# HELP go_errors_kind_total Errors created, by kind.\n# TYPE go_errors_kind_total counter\n")
+	assert.Contains(t, body, fmt.Sprintf("go_errors_kind_total{kind=\"NotFoundError\"} %d\n", counter(kindCounts, "NotFoundError")))
+	assert.Contains(t, body, fmt.Sprintf("go_errors_code_total{code=\"NOT_FOUND\"} %d\n", counter(codeCounts, "NOT_FOUND")))
+	assert.Contains(t, body, "# TYPE go_errors_reason_total counter\n")
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		assert.True(t, strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "go_errors_"), line)
+	}
+
+	// label values are escaped
+	c := &counters{}
+	c.add("QUOTED\"REASON")
+	w = httptest.NewRecorder()
+	writeCounter(w, "foo_total", "Foo.", "reason", c)
+	assert.Equal(t, "# HELP foo_total Foo.\n# TYPE foo_total counter\nfoo_total{reason=\"QUOTED\\\"REASON\"} 1\n", w.Body.String())
+}
@@ -0,0 +1,20 @@
+// Package metricsvar publishes the error counters of
+// github.com/weathersource/go-errors with expvar. It is kept apart from that
+// package because importing expvar registers the /debug/vars handler on
+// http.DefaultServeMux.
+//
+//	expvar.Publish("go-errors", metricsvar.MetricsVar())
+package metricsvar
+
+import (
+	"expvar"
+
+	errors "github.com/weathersource/go-errors"
+)
+
+// MetricsVar returns an expvar.Var whose value is the current error counters,
+// as returned by errors.Metrics, keyed by "kind", "code" and "reason", then by
+// label.
+func MetricsVar() expvar.Var {
+	return expvar.Func(func() any { return errors.Metrics() })
+}
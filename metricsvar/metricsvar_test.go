@@ -0,0 +1,23 @@
+package metricsvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	errors "github.com/weathersource/go-errors"
+)
+
+func TestMetricsVar(t *testing.T) {
+	expvar.Publish("go-errors-test", MetricsVar())
+	before := errors.Metrics()["kind"]["NotFoundError"]
+	errors.NewNotFoundError("foo")
+
+	var metrics map[string]map[string]int64
+	if assert.Nil(t, json.Unmarshal([]byte(expvar.Get("go-errors-test").String()), &metrics)) {
+		assert.Equal(t, before+1, metrics["kind"]["NotFoundError"])
+		assert.Contains(t, metrics["code"], "NOT_FOUND")
+		assert.Contains(t, metrics, "reason")
+	}
+}
@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	codes "google.golang.org/grpc/codes"
+)
+
+// NetClassifier classifies network errors from the net, net/url, net/http and
+// crypto/tls packages:
+//
+//	*net.DNSError timing out               DEADLINE_EXCEEDED
+//	other *net.DNSError                    UNAVAILABLE
+//	TLS certificate verification failures  INTERNAL
+//	other TLS handshake failures           UNAVAILABLE
+//	*net.OpError wrapping a known errno    as by ErrnoClassifier
+//	*net.OpError timing out                DEADLINE_EXCEEDED
+//	*net.OpError while dialing or writing  UNAVAILABLE
+//	*net.OpError while reading             UNKNOWN
+//	http.ErrHandlerTimeout                 DEADLINE_EXCEEDED
+//
+// A failed read leaves the outcome of the request unknown, so it is not
+// classified as UNAVAILABLE unless the errno shows the connection was reset.
+// Errors wrapped in a *url.Error are classified by the error they wrap.
+//
+// Errors returned by NewPassthroughError record a reason, such as
+// DNS_NOT_FOUND, along with the remote address, host or URL in their
+// metadata.
+var NetClassifier Classifier = ClassifierFunc(classifyNet)
+
+// Reasons recorded for network errors by NewPassthroughError
+const (
+	ReasonDNSNotFound           = "DNS_NOT_FOUND"
+	ReasonDNSTimeout            = "DNS_TIMEOUT"
+	ReasonDNSFailure            = "DNS_FAILURE"
+	ReasonTLSCertificateInvalid = "TLS_CERTIFICATE_INVALID"
+	ReasonTLSHandshakeFailed    = "TLS_HANDSHAKE_FAILED"
+	ReasonDialFailed            = "DIAL_FAILED"
+	ReasonReadFailed            = "READ_FAILED"
+	ReasonWriteFailed           = "WRITE_FAILED"
+)
+
+// classifyNet implements NetClassifier.
+func classifyNet(err error) codes.Code {
+	if errors.Is(err, http.ErrHandlerTimeout) {
+		return codes.DeadlineExceeded
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return codes.DeadlineExceeded
+		}
+		return codes.Unavailable
+	}
+
+	switch tlsReason(err) {
+	case ReasonTLSCertificateInvalid:
+		return codes.Internal
+	case ReasonTLSHandshakeFailed:
+		return codes.Unavailable
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		if c := ErrnoClassifier.Classify(opErr.Err); c != codes.OK {
+			return c
+		}
+		switch {
+		case opErr.Timeout():
+			return codes.DeadlineExceeded
+		case opErr.Op == "read":
+			return codes.Unknown
+		default:
+			return codes.Unavailable
+		}
+	}
+	return codes.OK
+}
+
+// tlsReason returns the reason for TLS handshake failures, or "" if err is not
+// one.
+func tlsReason(err error) string {
+	var (
+		verificationErr *tls.CertificateVerificationError
+		authorityErr    x509.UnknownAuthorityError
+		hostnameErr     x509.HostnameError
+		invalidErr      x509.CertificateInvalidError
+		recordErr       tls.RecordHeaderError
+		alertErr        tls.AlertError
+	)
+	switch {
+	case errors.As(err, &verificationErr),
+		errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr):
+		return ReasonTLSCertificateInvalid
+	case errors.As(err, &recordErr),
+		errors.As(err, &alertErr):
+		return ReasonTLSHandshakeFailed
+	}
+	return ""
+}
+
+// describeNet records the reason and remote address, host or URL of network
+// errors in info.
+func describeNet(err error, info *errorInfo) {
+	setReason := func(reason string) {
+		if info.reason == "" {
+			info.reason = reason
+		}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			setReason(ReasonDNSNotFound)
+		case dnsErr.IsTimeout:
+			setReason(ReasonDNSTimeout)
+		default:
+			setReason(ReasonDNSFailure)
+		}
+		info.metadata["host"] = dnsErr.Name
+		if dnsErr.Server != "" {
+			info.metadata["dns_server"] = dnsErr.Server
+		}
+	}
+
+	if reason := tlsReason(err); reason != "" {
+		setReason(reason)
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		switch opErr.Op {
+		case "dial":
+			setReason(ReasonDialFailed)
+		case "read":
+			setReason(ReasonReadFailed)
+		case "write":
+			setReason(ReasonWriteFailed)
+		}
+		info.metadata["op"] = opErr.Op
+		info.metadata["network"] = opErr.Net
+		if opErr.Addr != nil {
+			info.metadata["address"] = opErr.Addr.String()
+		}
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		info.metadata["url"] = urlErr.URL
+	}
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+)
+
+func TestNetClassifier(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 443}
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{&net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}, codes.Unavailable},
+		{&net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}, codes.DeadlineExceeded},
+		{&net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true}, codes.Unavailable},
+		{&net.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: &net.DNSError{IsNotFound: true}}, codes.Unavailable},
+		{&net.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: errors.New("connection refused")}, codes.Unavailable},
+		{&net.OpError{Op: "write", Net: "tcp", Addr: addr, Err: errors.New("broken pipe")}, codes.Unavailable},
+		{&net.OpError{Op: "read", Net: "tcp", Addr: addr, Err: errors.New("connection reset")}, codes.Unknown},
+		{&net.OpError{Op: "read", Net: "tcp", Addr: addr, Err: timeoutError{timeout: true}}, codes.DeadlineExceeded},
+		{&url.Error{Op: "Get", URL: "https://example.com", Err: &net.OpError{Op: "dial", Err: errors.New("foo")}}, codes.Unavailable},
+		{&url.Error{Op: "Get", URL: "https://example.com", Err: x509.UnknownAuthorityError{}}, codes.Internal},
+		{&tls.CertificateVerificationError{Err: errors.New("foo")}, codes.Internal},
+		{x509.HostnameError{Host: "example.com", Certificate: &x509.Certificate{}}, codes.Internal},
+		{tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, codes.Unavailable},
+		{&net.OpError{Op: "remote error", Err: tls.AlertError(40)}, codes.Unavailable},
+		{http.ErrHandlerTimeout, codes.DeadlineExceeded},
+		{errors.New("foo"), codes.OK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, NetClassifier.Classify(test.err), test.err.Error())
+	}
+}
+
+func TestPassthroughNetMetadata(t *testing.T) {
+	dnsErr := &net.DNSError{Err: "no such host", Name: "example.invalid", Server: "10.0.0.1:53", IsNotFound: true}
+	err := &url.Error{
+		Op:  "Get",
+		URL: "https://example.invalid/foo",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: dnsErr},
+	}
+	res := NewPassthroughError("foo", err)
+	unavailable, ok := res.(*UnavailableError)
+	if assert.True(t, ok) {
+		assert.Equal(t, ReasonDNSNotFound, unavailable.GetReason())
+		assert.Equal(t, map[string]string{
+			"host":       "example.invalid",
+			"dns_server": "10.0.0.1:53",
+			"op":         "dial",
+			"network":    "tcp",
+			"url":        "https://example.invalid/foo",
+		}, unavailable.GetMetadata())
+	}
+
+	// a real connection refused
+	ln, lErr := net.Listen("tcp", "127.0.0.1:0")
+	if assert.Nil(t, lErr) {
+		address := ln.Addr().String()
+		ln.Close()
+		_, dErr := net.Dial("tcp", address)
+		if assert.NotNil(t, dErr) {
+			res = NewPassthroughError("foo", dErr)
+			unavailable, ok = res.(*UnavailableError)
+			if assert.True(t, ok) {
+				assert.Equal(t, ReasonDialFailed, unavailable.GetReason())
+				assert.Equal(t, address, unavailable.GetMetadata()["address"])
+			}
+		}
+	}
+
+	res = NewPassthroughError("foo", &net.DNSError{Name: "example.com", IsTimeout: true})
+	assert.IsType(t, &DeadlineExceededError{}, res)
+	assert.Equal(t, ReasonDNSTimeout, res.(*DeadlineExceededError).GetReason())
+
+	res = NewPassthroughError("foo", x509.UnknownAuthorityError{})
+	assert.Equal(t, ReasonTLSCertificateInvalid, res.(*InternalError).GetReason())
+}
@@ -113,6 +113,24 @@ func TestNotFoundErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestNotFoundErrorGetReason(t *testing.T) {
+	for _, test := range NotFoundErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewNotFoundError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestNotFoundErrorGetMetadata(t *testing.T) {
+	for _, test := range NotFoundErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewNotFoundError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestNotFoundErrorJson(t *testing.T) {
 	for _, test := range NotFoundErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -16,11 +16,13 @@ import (
 //
 // RPC Mapping: NOT_IMPLEMENTED
 type NotImplementedError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code     int    `json:"errorCode"`
+	Message  string `json:"errorMessage"`
+	cause    error
+	stack    stack
+	reason   string
+	metadata map[string]string
+	rpcCode  codes.Code
 }
 
 // NewNotImplementedError returns a new NotImplementedError.
@@ -29,13 +31,15 @@ func NewNotImplementedError(Message string, cause ...error) *NotImplementedError
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &NotImplementedError{
+	e := &NotImplementedError{
 		Code:    501,
 		Message: "NOT IMPLEMENTED. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.Unimplemented,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -45,6 +49,9 @@ func (e *NotImplementedError) Error() string { return errorStr(e) }
 func (e *NotImplementedError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *NotImplementedError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -59,6 +66,18 @@ func (e *NotImplementedError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *NotImplementedError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *NotImplementedError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *NotImplementedError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *NotImplementedError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *NotImplementedError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *NotImplementedError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
@@ -113,6 +113,24 @@ func TestNotImplementedErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestNotImplementedErrorGetReason(t *testing.T) {
+	for _, test := range NotImplementedErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewNotImplementedError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestNotImplementedErrorGetMetadata(t *testing.T) {
+	for _, test := range NotImplementedErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewNotImplementedError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestNotImplementedErrorJson(t *testing.T) {
 	for _, test := range NotImplementedErrorTests {
 		json, _ := json.Marshal(test.err)
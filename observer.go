@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// An Observer is called with each error created by the constructors of this
+// package, such as NewNotFoundError, or returned by NewPassthroughError or
+// FromContext, and the frame of the function that created it outside this
+// package. Observers are called synchronously, so they should be quick, and
+// may be called concurrently.
+type Observer func(err error, caller runtime.Frame)
+
+// registeredObserver is an Observer added with RegisterObserver.
+type registeredObserver struct {
+	id       int
+	observer Observer
+}
+
+// observers stores the registered observers. The list is replaced rather than
+// modified, so it may be loaded without locking.
+var observers struct {
+	sync.Mutex
+	nextID int
+	list   atomic.Pointer[[]registeredObserver]
+}
+
+// RegisterObserver registers o to be called with each error created. The
+// returned function unregisters o.
+func RegisterObserver(o Observer) (unregister func()) {
+	observers.Lock()
+	defer observers.Unlock()
+
+	id := observers.nextID
+	observers.nextID++
+	var list []registeredObserver
+	if old := observers.list.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, registeredObserver{id: id, observer: o})
+	observers.list.Store(&list)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			observers.Lock()
+			defer observers.Unlock()
+			old := *observers.list.Load()
+			list := make([]registeredObserver, 0, len(old))
+			for _, r := range old {
+				if r.id != id {
+					list = append(list, r)
+				}
+			}
+			observers.list.Store(&list)
+		})
+	}
+}
+
+// created is called by the constructors of this package with each error they
+// create.
+func created(err error) {
+	countError(err)
+	observe(err, true)
+}
+
+// observe calls the registered observers with err. If fromConstructor is true
+// and err is being created by a function that observes the errors it returns
+// itself, such as NewPassthroughError, the observers are not called.
+func observe(err error, fromConstructor bool) {
+	list := observers.list.Load()
+	if list == nil || len(*list) == 0 {
+		return
+	}
+	caller, observed := callerFrame()
+	if fromConstructor && observed {
+		return
+	}
+	for _, r := range *list {
+		r.observer(err, caller)
+	}
+}
+
+// libraryPrefix is the prefix of the names of the functions of this package.
+const libraryPrefix = "github.com/weathersource/go-errors."
+
+// observingFunctions are the functions of this package calling observe with
+// the errors they return.
+var observingFunctions = map[string]bool{
+	libraryPrefix + "PassthroughPolicy.NewPassthroughError": true,
+	libraryPrefix + "FromContext":                           true,
+}
+
+// callerFrame returns the first frame of the calling goroutine's stack outside
+// this package, and whether one of the frames of this package above it is an
+// observing function.
+func callerFrame() (caller runtime.Frame, observed bool) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !isLibraryFrame(f) {
+			return f, observed
+		}
+		if observingFunctions[f.Function] {
+			observed = true
+		}
+		if !more {
+			return runtime.Frame{}, observed
+		}
+	}
+}
+
+// isLibraryFrame reports whether f is a frame of a function of this package,
+// other than of its tests.
+func isLibraryFrame(f runtime.Frame) bool {
+	return strings.HasPrefix(f.Function, libraryPrefix) && !strings.HasSuffix(f.File, "_test.go")
+}
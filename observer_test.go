@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+// observation is an error and caller frame passed to an Observer.
+type observation struct {
+	err    error
+	caller runtime.Frame
+}
+
+// recordObservations registers an observer recording its observations until
+// the test ends.
+func recordObservations(t *testing.T) func() []observation {
+	var mu sync.Mutex
+	var obs []observation
+	unregister := RegisterObserver(func(err error, caller runtime.Frame) {
+		mu.Lock()
+		defer mu.Unlock()
+		obs = append(obs, observation{err, caller})
+	})
+	t.Cleanup(unregister)
+	return func() []observation {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]observation(nil), obs...)
+	}
+}
+
+func TestRegisterObserver(t *testing.T) {
+	observations := recordObservations(t)
+
+	err := NewNotFoundError("foo")
+	obs := observations()
+	if assert.Equal(t, 1, len(obs)) {
+		assert.Equal(t, err, obs[0].err)
+		assert.Equal(t, "github.com/weathersource/go-errors.TestRegisterObserver", obs[0].caller.Function)
+		assert.True(t, strings.HasSuffix(obs[0].caller.File, "observer_test.go"))
+	}
+
+	// errors created within this package are attributed to their caller
+	NewErrors(NewInternalError("foo"))
+	assert.Equal(t, 2, len(observations()))
+}
+
+func TestObserverPassthrough(t *testing.T) {
+	observations := recordObservations(t)
+
+	err := NewPassthroughError("foo", errors.New("bar"))
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrServerShutdown)
+	canceled := FromContext(ctx)
+
+	obs := observations()
+	if assert.Equal(t, 2, len(obs)) {
+		assert.Equal(t, err, obs[0].err)
+		assert.Equal(t, "github.com/weathersource/go-errors.TestObserverPassthrough", obs[0].caller.Function)
+		assert.Equal(t, canceled, obs[1].err)
+		assert.Equal(t, ReasonServerShutdown, obs[1].err.(*CanceledError).GetReason())
+		assert.Equal(t, "github.com/weathersource/go-errors.TestObserverPassthrough", obs[1].caller.Function)
+	}
+}
+
+func TestUnregisterObserver(t *testing.T) {
+	var calls [2]int
+	unregister0 := RegisterObserver(func(error, runtime.Frame) { calls[0]++ })
+	unregister1 := RegisterObserver(func(error, runtime.Frame) { calls[1]++ })
+
+	NewUnknownError("foo")
+	unregister0()
+	unregister0()
+	NewUnknownError("foo")
+	unregister1()
+	NewUnknownError("foo")
+
+	assert.Equal(t, [2]int{1, 2}, calls)
+	assert.Equal(t, 0, len(*observers.list.Load()))
+}
+
+func TestObserverConcurrency(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				unregister := RegisterObserver(func(error, runtime.Frame) {})
+				NewAbortedError("foo")
+				unregister()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, len(*observers.list.Load()))
+}
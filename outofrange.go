@@ -25,15 +25,21 @@ import (
 //
 //		OUT OF RANGE. Parameter 'age' is out of range [0, 125].
 //
+// Any field violations are marshalled to JSON and returned via GRPC status as
+// a BadRequest detail.
+//
 // HTTP Mapping: 400 BAD REQUEST
 //
 // RPC Mapping: OUT_OF_RANGE
 type OutOfRangeError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code            int              `json:"errorCode"`
+	Message         string           `json:"errorMessage"`
+	FieldViolations []FieldViolation `json:"fieldViolations,omitempty"`
+	cause           error
+	stack           stack
+	reason          string
+	metadata        map[string]string
+	rpcCode         codes.Code
 }
 
 // NewOutOfRangeError returns a new OutOfRangeError.
@@ -42,13 +48,15 @@ func NewOutOfRangeError(Message string, cause ...error) *OutOfRangeError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &OutOfRangeError{
+	e := &OutOfRangeError{
 		Code:    400,
 		Message: "OUT OF RANGE. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.OutOfRange,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -58,6 +66,9 @@ func (e *OutOfRangeError) Error() string { return errorStr(e) }
 func (e *OutOfRangeError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *OutOfRangeError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -72,7 +83,28 @@ func (e *OutOfRangeError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *OutOfRangeError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *OutOfRangeError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *OutOfRangeError) setReason(reason string) { e.reason = reason }
+
+// GetFieldViolations returns the field violations associated with this error.
+func (e *OutOfRangeError) GetFieldViolations() []FieldViolation { return e.FieldViolations }
+
+// AddFieldViolation adds a violation of the request field identified by field
+// to this error.
+func (e *OutOfRangeError) AddFieldViolation(field, description string) {
+	e.FieldViolations = append(e.FieldViolations, FieldViolation{Field: field, Description: description})
+}
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *OutOfRangeError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *OutOfRangeError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *OutOfRangeError) GRPCStatus() *status.Status {
-	return status.New(e.rpcCode, e.Message)
+	return withFieldViolations(status.New(e.rpcCode, e.Message), e.FieldViolations)
 }
@@ -3,9 +3,11 @@ package errors
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
 
 	assert "github.com/stretchr/testify/assert"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
 	codes "google.golang.org/grpc/codes"
 )
 
@@ -113,6 +115,24 @@ func TestOutOfRangeErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestOutOfRangeErrorGetReason(t *testing.T) {
+	for _, test := range OutOfRangeErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewOutOfRangeError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestOutOfRangeErrorGetMetadata(t *testing.T) {
+	for _, test := range OutOfRangeErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewOutOfRangeError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestOutOfRangeErrorJson(t *testing.T) {
 	for _, test := range OutOfRangeErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -127,3 +147,26 @@ func TestOutOfRangeErrorGrpc(t *testing.T) {
 		assert.Equal(t, test.rpcMessage, s.Message())
 	}
 }
+
+func TestOutOfRangeErrorFieldViolations(t *testing.T) {
+	for _, test := range OutOfRangeErrorTests {
+		assert.Nil(t, test.err.GetFieldViolations())
+		assert.Equal(t, 0, len(test.err.GRPCStatus().Details()))
+	}
+
+	err := NewOutOfRangeError("Message")
+	err.AddFieldViolation("foo", "bar")
+	err.AddFieldViolation("baz", "bat")
+	assert.Equal(t, []FieldViolation{{"foo", "bar"}, {"baz", "bat"}}, err.GetFieldViolations())
+
+	j, _ := json.Marshal(err)
+	assert.Equal(t, `{"errorCode":`+strconv.Itoa(err.GetCode())+`,"errorMessage":"`+err.GetMessage()+`","fieldViolations":[{"field":"foo","description":"bar"},{"field":"baz","description":"bat"}]}`, string(j))
+
+	details := err.GRPCStatus().Details()
+	if assert.Equal(t, 1, len(details)) {
+		br := details[0].(*errdetails.BadRequest)
+		assert.Equal(t, 2, len(br.GetFieldViolations()))
+		assert.Equal(t, "foo", br.GetFieldViolations()[0].GetField())
+		assert.Equal(t, "bar", br.GetFieldViolations()[0].GetDescription())
+	}
+}
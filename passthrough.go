@@ -1,54 +1,240 @@
 package errors
 
 import (
-	"context"
+	"net/http"
+	"time"
 
 	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
 )
 
-// NewPassthroughError handles an error from an external dependency. If the error is a
-// timeout, canceled, unavailable, unknown, or temporary error, it is passed through as
-// the appropriate correlating type from this package. Otherwise, an internal error
-// with the provided message is returned.
-func NewPassthroughError(msg string, err error) error {
+// PassthroughPolicy determines which kinds of errors from an external
+// dependency are passed through as the correlating type from this package.
+// Errors of any other kind are masked as an InternalError. The zero
+// PassthroughPolicy masks every error.
+//
+// Masked errors carrying the HTTP status of an upstream response (see
+// HTTPClassifier) are reported as 502 BAD GATEWAY. Whether upstream 4xx
+// responses are passed through may be decided independently of their kind with
+// SurfaceUpstream4xx and MaskUpstream4xx.
+type PassthroughPolicy struct {
+	expose      map[codes.Code]bool
+	upstream4xx upstreamMode
+}
+
+// upstreamMode determines how a PassthroughPolicy handles upstream HTTP 4xx
+// responses.
+type upstreamMode int
+
+const (
+	upstreamByKind upstreamMode = iota
+	upstreamSurface
+	upstreamMask
+)
+
+// Passthrough policies for use with SetPassthroughPolicy or at the call site.
+var (
+	// DefaultPassthroughPolicy passes through canceled, timeout, unavailable,
+	// and unknown errors.
+	DefaultPassthroughPolicy = NewPassthroughPolicy(
+		codes.Canceled,
+		codes.DeadlineExceeded,
+		codes.Unavailable,
+		codes.Unknown,
+	)
+
+	// ClientPassthroughPolicy additionally passes through client errors, such
+	// as not found or invalid argument errors, for use when the dependency's
+	// client errors are safe to re-expose to the caller.
+	ClientPassthroughPolicy = NewPassthroughPolicy(
+		codes.Canceled,
+		codes.DeadlineExceeded,
+		codes.Unavailable,
+		codes.Unknown,
+		codes.InvalidArgument,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.ResourceExhausted,
+		codes.FailedPrecondition,
+		codes.Aborted,
+		codes.OutOfRange,
+		codes.Unauthenticated,
+	)
+
+	// FullPassthroughPolicy passes through every kind of error.
+	FullPassthroughPolicy = NewPassthroughPolicy(
+		codes.Canceled,
+		codes.Unknown,
+		codes.InvalidArgument,
+		codes.DeadlineExceeded,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.ResourceExhausted,
+		codes.FailedPrecondition,
+		codes.Aborted,
+		codes.OutOfRange,
+		codes.Unimplemented,
+		codes.Internal,
+		codes.Unavailable,
+		codes.DataLoss,
+		codes.Unauthenticated,
+	)
+)
+
+// passthroughPolicy stores the global policy used by NewPassthroughError.
+var passthroughPolicy = DefaultPassthroughPolicy
 
-	// test err against target interfaces
-	sErr, sOk := err.(interface{ GRPCStatus() *(status.Status) })
-	tiErr, tiOk := err.(interface{ Timeout() bool })
-	teErr, teOk := err.(interface{ Temporary() bool })
+// SetPassthroughPolicy changes the global policy used by NewPassthroughError.
+func SetPassthroughPolicy(p PassthroughPolicy) { passthroughPolicy = p }
 
-	// CanceledError
-	switch {
-	case err == context.Canceled:
-		fallthrough
-	case sOk && codes.Canceled == sErr.GRPCStatus().Code():
-		return NewCanceledError(msg, err)
+// NewPassthroughPolicy returns a PassthroughPolicy passing through errors of
+// the kinds identified by the gRPC codes in expose.
+func NewPassthroughPolicy(expose ...codes.Code) PassthroughPolicy {
+	p := PassthroughPolicy{expose: make(map[codes.Code]bool, len(expose))}
+	for _, c := range expose {
+		p.expose[c] = true
 	}
+	return p
+}
+
+// SurfaceUpstream4xx returns a copy of p that passes through errors carrying an
+// upstream HTTP 4xx status as the correlating client error, whatever their
+// kind.
+func (p PassthroughPolicy) SurfaceUpstream4xx() PassthroughPolicy {
+	p.upstream4xx = upstreamSurface
+	return p
+}
+
+// MaskUpstream4xx returns a copy of p that masks errors carrying an upstream
+// HTTP 4xx status as a 502 BAD GATEWAY InternalError, whatever their kind. Use
+// it when an upstream client error indicates a fault in the request we made
+// rather than in our caller's request.
+func (p PassthroughPolicy) MaskUpstream4xx() PassthroughPolicy {
+	p.upstream4xx = upstreamMask
+	return p
+}
+
+// Exposes reports whether p passes through errors of the kind identified by
+// the gRPC code c.
+func (p PassthroughPolicy) Exposes(c codes.Code) bool { return p.expose[c] }
 
-	// DeadlineExceededError
-	switch {
-	case err == context.DeadlineExceeded:
-		fallthrough
-	case sOk && codes.DeadlineExceeded == sErr.GRPCStatus().Code():
-		fallthrough
-	case tiOk && tiErr.Timeout():
-		return NewDeadlineExceededError(msg, err)
+// NewPassthroughError handles an error from an external dependency. The error
+// is classified with Classify, and if p exposes its kind, it is passed through
+// as the appropriate correlating type from this package, keeping any retry
+// delay requested by the dependency (see RetryDelay). Otherwise, an internal
+// error with the provided message is returned.
+func (p PassthroughPolicy) NewPassthroughError(msg string, err error) error {
+	c := Classify(err)
+	if !p.exposes(c, err) {
+		c = codes.Internal
 	}
+	res := NewError(c, msg, err)
+	if internal, ok := res.(*InternalError); ok {
+		if _, upstream := upstreamStatus(err); upstream {
+			internal.Code = http.StatusBadGateway
+		}
+	}
+	info := describe(err)
+	if info.reason != "" {
+		res.(interface{ setReason(string) }).setReason(info.reason)
+		countReason(info.reason)
+	}
+	if len(info.metadata) > 0 {
+		res.(interface{ setMetadata(map[string]string) }).setMetadata(info.metadata)
+	}
+	if delayed, ok := res.(interface{ SetRetryDelay(time.Duration) }); ok {
+		if d, ok := RetryDelay(err); ok {
+			delayed.SetRetryDelay(d)
+		}
+	}
+	observe(res, false)
+	return res
+}
 
-	// UnavailableError
-	if sOk && codes.Unavailable == sErr.GRPCStatus().Code() {
-		return NewUnavailableError(msg, err)
+// exposes reports whether p passes through err, which is of the kind
+// identified by the gRPC code c.
+func (p PassthroughPolicy) exposes(c codes.Code, err error) bool {
+	if p.upstream4xx != upstreamByKind {
+		if status, ok := upstreamStatus(err); ok && isClientStatus(status) {
+			return p.upstream4xx == upstreamSurface
+		}
 	}
+	return p.Exposes(c)
+}
 
-	// UnknownError
-	switch {
-	case sOk && codes.Unknown == sErr.GRPCStatus().Code():
-		fallthrough
-	case teOk && teErr.Temporary():
-		return NewUnknownError(msg, err)
+// NewPassthroughError handles an error from an external dependency using the
+// global passthrough policy (see SetPassthroughPolicy). With the default
+// policy, if the error is a timeout, canceled, unavailable, unknown, or
+// temporary error, it is passed through as the appropriate correlating type
+// from this package. Otherwise, an internal error with the provided message is
+// returned.
+func NewPassthroughError(msg string, err error) error {
+	return passthroughPolicy.NewPassthroughError(msg, err)
+}
+
+// errorInfo is structured information about an error from an external
+// dependency.
+type errorInfo struct {
+	reason   string
+	metadata map[string]string
+}
+
+// describers record structured information about an error from an external
+// dependency. A describer only sets the reason if it is not already set.
+var describers = []func(err error, info *errorInfo){
+	describeHTTP,
+	describeNet,
+	describeFS,
+	describeSQL,
+	describeErrno,
+}
+
+// describe returns the structured information recorded about err by
+// describers.
+func describe(err error) errorInfo {
+	info := errorInfo{metadata: make(map[string]string)}
+	for _, d := range describers {
+		d(err, &info)
 	}
+	return info
+}
 
-	// InternalError
-	return NewInternalError(msg, err)
+// NewError returns a new error of the type from this package correlating to
+// the gRPC code c, such as a NotFoundError for codes.NotFound. Codes without a
+// correlating type, including codes.OK, yield an InternalError.
+func NewError(c codes.Code, msg string, cause ...error) error {
+	switch c {
+	case codes.Aborted:
+		return NewAbortedError(msg, cause...)
+	case codes.AlreadyExists:
+		return NewAlreadyExistsError(msg, cause...)
+	case codes.Canceled:
+		return NewCanceledError(msg, cause...)
+	case codes.DataLoss:
+		return NewDataLossError(msg, cause...)
+	case codes.DeadlineExceeded:
+		return NewDeadlineExceededError(msg, cause...)
+	case codes.FailedPrecondition:
+		return NewFailedPreconditionError(msg, cause...)
+	case codes.InvalidArgument:
+		return NewInvalidArgumentError(msg, cause...)
+	case codes.NotFound:
+		return NewNotFoundError(msg, cause...)
+	case codes.OutOfRange:
+		return NewOutOfRangeError(msg, cause...)
+	case codes.PermissionDenied:
+		return NewPermissionDeniedError(msg, cause...)
+	case codes.ResourceExhausted:
+		return NewResourceExhaustedError(msg, cause...)
+	case codes.Unauthenticated:
+		return NewUnauthenticatedError(msg, cause...)
+	case codes.Unavailable:
+		return NewUnavailableError(msg, cause...)
+	case codes.Unimplemented:
+		return NewNotImplementedError(msg, cause...)
+	case codes.Unknown:
+		return NewUnknownError(msg, cause...)
+	}
+	return NewInternalError(msg, cause...)
 }
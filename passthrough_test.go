@@ -2,9 +2,11 @@ package errors
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 )
 
@@ -94,3 +96,80 @@ func TestNewPassthroughError(t *testing.T) {
 		assert.Equal(t, r.Message(), e.Message())
 	}
 }
+
+func TestPassthroughPolicy(t *testing.T) {
+	var tests = []struct {
+		policy PassthroughPolicy
+		err    error
+		code   codes.Code
+	}{
+		{DefaultPassthroughPolicy, NewNotFoundError("bar"), codes.Internal},
+		{DefaultPassthroughPolicy, status.Error(codes.InvalidArgument, "bar"), codes.Internal},
+		{DefaultPassthroughPolicy, NewUnavailableError("bar"), codes.Unavailable},
+		{ClientPassthroughPolicy, NewNotFoundError("bar"), codes.NotFound},
+		{ClientPassthroughPolicy, status.Error(codes.InvalidArgument, "bar"), codes.InvalidArgument},
+		{ClientPassthroughPolicy, status.Error(codes.PermissionDenied, "bar"), codes.PermissionDenied},
+		{ClientPassthroughPolicy, NewDataLossError("bar"), codes.Internal},
+		{ClientPassthroughPolicy, NewNotImplementedError("bar"), codes.Internal},
+		{ClientPassthroughPolicy, context.DeadlineExceeded, codes.DeadlineExceeded},
+		{FullPassthroughPolicy, NewDataLossError("bar"), codes.DataLoss},
+		{FullPassthroughPolicy, status.Error(codes.Unimplemented, "bar"), codes.Unimplemented},
+		{FullPassthroughPolicy, errors.New("bar"), codes.Internal},
+		{NewPassthroughPolicy(codes.NotFound), NewNotFoundError("bar"), codes.NotFound},
+		{NewPassthroughPolicy(codes.NotFound), NewUnavailableError("bar"), codes.Internal},
+		{PassthroughPolicy{}, NewNotFoundError("bar"), codes.Internal},
+	}
+	for _, test := range tests {
+		res := test.policy.NewPassthroughError("foo", test.err)
+		assert.Equal(t, test.code, status.Code(res))
+	}
+
+	// every code maps onto the matching type
+	for c := codes.Canceled; c <= codes.Unauthenticated; c++ {
+		res := FullPassthroughPolicy.NewPassthroughError("foo", status.Error(c, "bar"))
+		assert.Equal(t, c, status.Code(res))
+		assert.Equal(t, status.Error(c, "bar").Error(), res.(interface{ GetCause() error }).GetCause().Error())
+	}
+}
+
+func TestSetPassthroughPolicy(t *testing.T) {
+	defer SetPassthroughPolicy(DefaultPassthroughPolicy)
+
+	err := NewNotFoundError("bar")
+	assert.IsType(t, &InternalError{}, NewPassthroughError("foo", err))
+
+	SetPassthroughPolicy(ClientPassthroughPolicy)
+	res := NewPassthroughError("foo", err)
+	assert.IsType(t, &NotFoundError{}, res)
+	assert.Equal(t, "NOT FOUND. foo", res.(*NotFoundError).GetMessage())
+}
+
+func TestNewError(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		exp  error
+	}{
+		{codes.Aborted, &AbortedError{}},
+		{codes.AlreadyExists, &AlreadyExistsError{}},
+		{codes.Canceled, &CanceledError{}},
+		{codes.DataLoss, &DataLossError{}},
+		{codes.DeadlineExceeded, &DeadlineExceededError{}},
+		{codes.FailedPrecondition, &FailedPreconditionError{}},
+		{codes.Internal, &InternalError{}},
+		{codes.InvalidArgument, &InvalidArgumentError{}},
+		{codes.NotFound, &NotFoundError{}},
+		{codes.OutOfRange, &OutOfRangeError{}},
+		{codes.PermissionDenied, &PermissionDeniedError{}},
+		{codes.ResourceExhausted, &ResourceExhaustedError{}},
+		{codes.Unauthenticated, &UnauthenticatedError{}},
+		{codes.Unavailable, &UnavailableError{}},
+		{codes.Unimplemented, &NotImplementedError{}},
+		{codes.Unknown, &UnknownError{}},
+		{codes.OK, &InternalError{}},
+	}
+	for _, test := range tests {
+		err := NewError(test.code, "foo", errors.New("bar"))
+		assert.IsType(t, test.exp, err)
+		assert.Equal(t, "bar", err.(interface{ GetCause() error }).GetCause().Error())
+	}
+}
@@ -19,11 +19,13 @@ import (
 //
 // RPC Mapping: PERMISSION_DENIED
 type PermissionDeniedError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code     int    `json:"errorCode"`
+	Message  string `json:"errorMessage"`
+	cause    error
+	stack    stack
+	reason   string
+	metadata map[string]string
+	rpcCode  codes.Code
 }
 
 // NewPermissionDeniedError returns a new PermissionDeniedError.
@@ -32,13 +34,15 @@ func NewPermissionDeniedError(Message string, cause ...error) *PermissionDeniedE
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &PermissionDeniedError{
+	e := &PermissionDeniedError{
 		Code:    403,
 		Message: "PERMISSION DENIED. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.PermissionDenied,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -48,6 +52,9 @@ func (e *PermissionDeniedError) Error() string { return errorStr(e) }
 func (e *PermissionDeniedError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *PermissionDeniedError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -62,6 +69,18 @@ func (e *PermissionDeniedError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *PermissionDeniedError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *PermissionDeniedError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *PermissionDeniedError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *PermissionDeniedError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *PermissionDeniedError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *PermissionDeniedError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
@@ -113,6 +113,24 @@ func TestPermissionDeniedErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestPermissionDeniedErrorGetReason(t *testing.T) {
+	for _, test := range PermissionDeniedErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewPermissionDeniedError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestPermissionDeniedErrorGetMetadata(t *testing.T) {
+	for _, test := range PermissionDeniedErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewPermissionDeniedError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestPermissionDeniedErrorJson(t *testing.T) {
 	for _, test := range PermissionDeniedErrorTests {
 		json, _ := json.Marshal(test.err)
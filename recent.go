@@ -0,0 +1,237 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCauseDepth bounds the depth of the cause trees reported by RecentErrors.
+const maxCauseDepth = 8
+
+// RecentErrors is a ring buffer of the last errors recorded, such as those
+// served by a live instance, for inspection by on-call engineers. It
+// implements http.Handler, rendering the errors grouped by Fingerprint as HTML,
+// or as JSON if requested with ?format=json or an Accept header of
+// application/json. It is safe for concurrent use by multiple goroutines.
+//
+// Nothing is recorded unless errors are passed to Record, or the errors
+// created by this package are recorded by registering Observe:
+//
+//	recent := errors.NewRecentErrors(1000)
+//	errors.RegisterObserver(recent.Observe)
+//	http.Handle("/debug/errors", recent)
+type RecentErrors struct {
+	mu      sync.Mutex
+	entries []recentEntry
+	next    int
+	full    bool
+	now     func() time.Time
+}
+
+// recentEntry is an error recorded by RecentErrors.
+type recentEntry struct {
+	err         error
+	fingerprint string
+	at          time.Time
+}
+
+// RecentErrorGroup describes the recorded errors sharing a fingerprint.
+type RecentErrorGroup struct {
+	Fingerprint string       `json:"fingerprint"`
+	Kind        string       `json:"kind"`
+	Reason      string       `json:"reason,omitempty"`
+	Message     string       `json:"message"`
+	Count       int          `json:"count"`
+	FirstSeen   time.Time    `json:"firstSeen"`
+	LastSeen    time.Time    `json:"lastSeen"`
+	Stack       []string     `json:"stack,omitempty"`
+	Causes      []ErrorCause `json:"causes,omitempty"`
+}
+
+// ErrorCause is a node of the cause tree of a recorded error.
+type ErrorCause struct {
+	Kind    string       `json:"kind"`
+	Message string       `json:"message"`
+	Causes  []ErrorCause `json:"causes,omitempty"`
+}
+
+// NewRecentErrors returns a RecentErrors holding the last n errors recorded.
+func NewRecentErrors(n int) *RecentErrors {
+	if n < 1 {
+		n = 1
+	}
+	return &RecentErrors{entries: make([]recentEntry, n), now: time.Now}
+}
+
+// Record records err, replacing the oldest error recorded if r is full. Nil
+// errors are ignored.
+func (r *RecentErrors) Record(err error) {
+	if err == nil {
+		return
+	}
+	fingerprint := Fingerprint(err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = recentEntry{err: err, fingerprint: fingerprint, at: r.now()}
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Observe records err. It is an Observer, for use with RegisterObserver.
+func (r *RecentErrors) Observe(err error, caller runtime.Frame) { r.Record(err) }
+
+// Groups returns the recorded errors grouped by fingerprint, most recently
+// seen first. Each group describes its most recent error.
+func (r *RecentErrors) Groups() []RecentErrorGroup {
+	entries := r.snapshot()
+
+	var groups []RecentErrorGroup
+	index := make(map[string]int)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if j, ok := index[e.fingerprint]; ok {
+			groups[j].Count++
+			groups[j].FirstSeen = e.at
+			continue
+		}
+		index[e.fingerprint] = len(groups)
+		groups = append(groups, RecentErrorGroup{
+			Fingerprint: e.fingerprint,
+			Kind:        codeLabel(Kind(e.err)),
+			Reason:      reasonOf(e.err),
+			Message:     messageOf(e.err),
+			Count:       1,
+			FirstSeen:   e.at,
+			LastSeen:    e.at,
+			Stack:       stackOf(e.err),
+			Causes:      causeTree(e.err, 0),
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].LastSeen.After(groups[j].LastSeen) })
+	return groups
+}
+
+// ServeHTTP renders the recorded errors grouped by fingerprint.
+func (r *RecentErrors) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	groups := r.Groups()
+	if req.URL.Query().Get("format") == "json" ||
+		strings.Contains(req.Header.Get("Accept"), "application/json") {
+		body, err := json.Marshal(struct {
+			Groups []RecentErrorGroup `json:"groups"`
+		}{groups})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := recentTemplate.Execute(w, groups); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// snapshot returns the recorded errors, oldest first.
+func (r *RecentErrors) snapshot() []recentEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]recentEntry(nil), r.entries[:r.next]...)
+	}
+	return append(append([]recentEntry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// messageOf returns the message of err.
+func messageOf(err error) string {
+	var msgErr interface{ GetMessage() string }
+	if errors.As(err, &msgErr) {
+		return msgErr.GetMessage()
+	}
+	return err.Error()
+}
+
+// stackOf returns the frames of the stack trace of err, if it has one.
+func stackOf(err error) []string {
+	var stackErr interface{ GetStack() stack }
+	if !errors.As(err, &stackErr) {
+		return nil
+	}
+	var frames []string
+	for _, f := range stackErr.GetStack() {
+		frames = append(frames, fmt.Sprintf("%s:%d %s", f.file, f.line, f.function))
+	}
+	return frames
+}
+
+// causeTree returns the cause tree of err, at depth in the tree.
+func causeTree(err error, depth int) []ErrorCause {
+	if depth >= maxCauseDepth {
+		return nil
+	}
+	var tree []ErrorCause
+	for _, cause := range causes(err) {
+		if cause == nil {
+			continue
+		}
+		tree = append(tree, ErrorCause{
+			Kind:    codeLabel(Kind(cause)),
+			Message: messageOf(cause),
+			Causes:  causeTree(cause, depth+1),
+		})
+	}
+	return tree
+}
+
+// recentTemplate renders RecentErrors groups as HTML.
+var recentTemplate = template.Must(template.New("recent").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>/debug/errors</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+pre { margin: 0; }
+</style>
+</head>
+<body>
+<h1>/debug/errors</h1>
+{{if .}}
+<table>
+<tr><th>Count</th><th>Kind</th><th>Reason</th><th>Message</th><th>First seen</th><th>Last seen</th><th>Fingerprint</th></tr>
+{{range .}}
+<tr>
+<td>{{.Count}}</td>
+<td>{{.Kind}}</td>
+<td>{{.Reason}}</td>
+<td>{{.Message}}
+{{if .Stack}}<details><summary>stack</summary><pre>{{range .Stack}}{{.}}
+{{end}}</pre></details>{{end}}
+{{if .Causes}}<details><summary>causes</summary>{{template "causes" .Causes}}</details>{{end}}
+</td>
+<td>{{.FirstSeen.Format "2006-01-02 15:04:05.000"}}</td>
+<td>{{.LastSeen.Format "2006-01-02 15:04:05.000"}}</td>
+<td><code>{{.Fingerprint}}</code></td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>No errors recorded.</p>
+{{end}}
+</body>
+</html>
+{{define "causes"}}<ul>{{range .}}<li>{{.Kind}}: {{.Message}}{{if .Causes}}{{template "causes" .Causes}}{{end}}</li>{{end}}</ul>{{end}}
+`))
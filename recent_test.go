@@ -0,0 +1,133 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+// newTestRecentErrors returns a RecentErrors whose clock advances by a second
+// with each error recorded.
+func newTestRecentErrors(n int) (*RecentErrors, time.Time) {
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	now := start
+	r := NewRecentErrors(n)
+	r.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return r, start
+}
+
+func newStationError(id string) error {
+	return NewNotFoundError("station " + id)
+}
+
+func TestRecentErrors(t *testing.T) {
+	r, start := newTestRecentErrors(10)
+	assert.Nil(t, r.Groups())
+
+	r.Record(nil)
+	r.Record(newStationError("KDEN"))
+	r.Record(NewInternalError("foo", NewUnavailableError("bar", errors.New("baz"))))
+	r.Record(newStationError("KBOS"))
+
+	groups := r.Groups()
+	if assert.Equal(t, 2, len(groups)) {
+		notFound := groups[0]
+		assert.Equal(t, Fingerprint(newStationError("KSEA")), notFound.Fingerprint)
+		assert.Equal(t, "NOT FOUND", notFound.Kind)
+		assert.Equal(t, "NOT FOUND. station KBOS", notFound.Message)
+		assert.Equal(t, 2, notFound.Count)
+		assert.Equal(t, start.Add(time.Second), notFound.FirstSeen)
+		assert.Equal(t, start.Add(3*time.Second), notFound.LastSeen)
+		assert.True(t, strings.HasPrefix(notFound.Stack[0], "recent_test.go:"), notFound.Stack[0])
+		assert.Nil(t, notFound.Causes)
+
+		internal := groups[1]
+		assert.Equal(t, 1, internal.Count)
+		assert.Equal(t, []ErrorCause{{
+			Kind:    "UNAVAILABLE",
+			Message: "UNAVAILABLE. Unable to handle the request due to a temporary overloading or maintenance. bar",
+			Causes:  []ErrorCause{{Kind: "INTERNAL ERROR", Message: "baz"}},
+		}}, internal.Causes)
+	}
+}
+
+func TestRecentErrorsRing(t *testing.T) {
+	r, start := newTestRecentErrors(3)
+	r.Record(NewInternalError("foo"))
+	for i := 0; i < 4; i++ {
+		r.Record(newStationError("KDEN"))
+	}
+
+	groups := r.Groups()
+	if assert.Equal(t, 1, len(groups)) {
+		assert.Equal(t, 3, groups[0].Count)
+		assert.Equal(t, start.Add(3*time.Second), groups[0].FirstSeen)
+		assert.Equal(t, start.Add(5*time.Second), groups[0].LastSeen)
+	}
+
+	assert.Equal(t, 1, len(NewRecentErrors(0).entries))
+}
+
+func TestRecentErrorsObserve(t *testing.T) {
+	r, _ := newTestRecentErrors(10)
+	unregister := RegisterObserver(r.Observe)
+	NewDataLossError("foo")
+	unregister()
+	NewDataLossError("foo")
+
+	groups := r.Groups()
+	if assert.Equal(t, 1, len(groups)) {
+		assert.Equal(t, "DATA LOSS", groups[0].Kind)
+		assert.Equal(t, 1, groups[0].Count)
+	}
+}
+
+func TestRecentErrorsServeHTTP(t *testing.T) {
+	r, _ := newTestRecentErrors(10)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors", nil))
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "No errors recorded.")
+
+	r.Record(NewInternalError("<script>", NewUnavailableError("bar")))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors", nil))
+	body := w.Body.String()
+	assert.Contains(t, body, "INTERNAL ERROR")
+	assert.Contains(t, body, "&lt;script&gt;")
+	assert.NotContains(t, body, "<script>")
+	assert.Contains(t, body, "<li>UNAVAILABLE: UNAVAILABLE.")
+	assert.Contains(t, body, "recent_test.go:")
+
+	jsonReqs := []struct {
+		url    string
+		accept string
+	}{
+		{"/debug/errors?format=json", ""},
+		{"/debug/errors", "application/json"},
+	}
+	for _, test := range jsonReqs {
+		req := httptest.NewRequest("GET", test.url, nil)
+		req.Header.Set("Accept", test.accept)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var res struct {
+			Groups []RecentErrorGroup `json:"groups"`
+		}
+		if assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &res)) && assert.Equal(t, 1, len(res.Groups)) {
+			assert.Equal(t, r.Groups()[0], res.Groups[0])
+		}
+	}
+}
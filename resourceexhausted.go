@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"time"
+
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 )
@@ -28,11 +30,14 @@ import (
 //
 // RPC Mapping: RESOURCE_EXHAUSTED
 type ResourceExhaustedError struct {
-	Code    int    `json:"errorCode"`
-	Message string `json:"errorMessage"`
-	cause   error
-	stack   stack
-	rpcCode codes.Code
+	Code       int    `json:"errorCode"`
+	Message    string `json:"errorMessage"`
+	cause      error
+	stack      stack
+	reason     string
+	metadata   map[string]string
+	retryDelay time.Duration
+	rpcCode    codes.Code
 }
 
 // NewResourceExhaustedError returns a new ResourceExhaustedError.
@@ -41,13 +46,15 @@ func NewResourceExhaustedError(Message string, cause ...error) *ResourceExhauste
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &ResourceExhaustedError{
+	e := &ResourceExhaustedError{
 		Code:    429,
 		Message: "RESOURCE EXHAUSTED. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.ResourceExhausted,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -57,6 +64,9 @@ func (e *ResourceExhaustedError) Error() string { return errorStr(e) }
 func (e *ResourceExhaustedError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *ResourceExhaustedError) Temporary() bool { return false }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -71,7 +81,27 @@ func (e *ResourceExhaustedError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *ResourceExhaustedError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *ResourceExhaustedError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *ResourceExhaustedError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *ResourceExhaustedError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *ResourceExhaustedError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
+// GetRetryDelay returns how long the client should wait before retrying, or
+// zero if no delay was set.
+func (e *ResourceExhaustedError) GetRetryDelay() time.Duration { return e.retryDelay }
+
+// SetRetryDelay sets how long the client should wait before retrying. The
+// delay is sent to gRPC clients in a RetryInfo detail.
+func (e *ResourceExhaustedError) SetRetryDelay(d time.Duration) { e.retryDelay = d }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *ResourceExhaustedError) GRPCStatus() *status.Status {
-	return status.New(e.rpcCode, e.Message)
+	return withRetryDelay(status.New(e.rpcCode, e.Message), e.retryDelay)
 }
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	assert "github.com/stretchr/testify/assert"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
 	codes "google.golang.org/grpc/codes"
 )
 
@@ -113,6 +115,40 @@ func TestResourceExhaustedErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestResourceExhaustedErrorGetReason(t *testing.T) {
+	for _, test := range ResourceExhaustedErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewResourceExhaustedError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestResourceExhaustedErrorGetMetadata(t *testing.T) {
+	for _, test := range ResourceExhaustedErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewResourceExhaustedError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
+func TestResourceExhaustedErrorRetryDelay(t *testing.T) {
+	for _, test := range ResourceExhaustedErrorTests {
+		assert.Equal(t, time.Duration(0), test.err.GetRetryDelay())
+		assert.Equal(t, 0, len(test.err.GRPCStatus().Details()))
+	}
+
+	err := NewResourceExhaustedError("Message")
+	err.SetRetryDelay(5 * time.Second)
+	assert.Equal(t, 5*time.Second, err.GetRetryDelay())
+
+	details := err.GRPCStatus().Details()
+	if assert.Equal(t, 1, len(details)) {
+		assert.Equal(t, 5*time.Second, details[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+	}
+}
+
 func TestResourceExhaustedErrorJson(t *testing.T) {
 	for _, test := range ResourceExhaustedErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -0,0 +1,117 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	errors "github.com/weathersource/go-errors"
+	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
+	status "google.golang.org/grpc/status"
+)
+
+// pushbackKey is the trailer in which a gRPC server may ask the client to
+// delay its next retry, in milliseconds, or not to retry, if negative.
+const pushbackKey = "grpc-retry-pushback-ms"
+
+// UnaryClientInterceptor returns a gRPC client interceptor retrying failed
+// unary calls to the idempotent methods, named as in "/package.Service/Method",
+// as Do does with p. Calls to other methods are never retried.
+//
+// Retries honor the RetryInfo details of returned statuses, the
+// grpc-retry-pushback-ms trailer, and the deadline of the call. If a call
+// fails without being retried, its error is returned unchanged. If a retried
+// call fails, the interceptor returns an error wrapping the error from
+// github.com/weathersource/go-errors correlating to the code of the final
+// failure, with the errors of all attempts as its causes. Its gRPC status is
+// that of the final failure, keeping the message and details sent by the
+// server.
+func UnaryClientInterceptor(p Policy, idempotent ...string) grpc.UnaryClientInterceptor {
+	methods := make(map[string]bool, len(idempotent))
+	for _, method := range idempotent {
+		methods[method] = true
+	}
+	p.NonIdempotent = false
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !methods[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		err := Do(ctx, p, func(ctx context.Context) error {
+			var trailer metadata.MD
+			callOpts := append(opts[:len(opts):len(opts)], grpc.Trailer(&trailer))
+			return pushback(invoker(ctx, method, req, reply, cc, callOpts...), trailer)
+		})
+		if err == nil {
+			return nil
+		}
+		return attemptsError(method, err.(*errors.Errors).Snapshot())
+	}
+}
+
+// pushback applies the pushback requested by the server in trailer to err.
+func pushback(err error, trailer metadata.MD) error {
+	if err == nil {
+		return nil
+	}
+	v := trailer.Get(pushbackKey)
+	if len(v) == 0 {
+		return err
+	}
+	ms, pErr := strconv.Atoi(v[0])
+	if pErr != nil || ms < 0 {
+		return Stop(err)
+	}
+	return &pushbackError{err: err, delay: time.Duration(ms) * time.Millisecond}
+}
+
+// pushbackError is an error for which the server requested a retry delay in
+// the grpc-retry-pushback-ms trailer.
+type pushbackError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *pushbackError) Error() string                { return e.err.Error() }
+func (e *pushbackError) Unwrap() error                { return e.err }
+func (e *pushbackError) GetRetryDelay() time.Duration { return e.delay }
+func (e *pushbackError) GRPCStatus() *status.Status   { return status.Convert(e.err) }
+
+// attemptsError returns the error for a call to method that failed after
+// attempts.
+func attemptsError(method string, attempts []error) error {
+	for i, err := range attempts {
+		if pErr, ok := err.(*pushbackError); ok {
+			attempts[i] = pErr.err
+		}
+	}
+	final := attempts[len(attempts)-1]
+	if len(attempts) == 1 {
+		return final
+	}
+	res := errors.NewError(
+		errors.Classify(final),
+		fmt.Sprintf("%s failed", method),
+		attempts...,
+	)
+	if delayed, ok := res.(interface{ SetRetryDelay(time.Duration) }); ok {
+		if d, ok := errors.RetryDelay(final); ok {
+			delayed.SetRetryDelay(d)
+		}
+	}
+	return &retriedError{err: res, status: status.Convert(final)}
+}
+
+// retriedError is the error for a call that failed after being retried. It
+// wraps the error correlating to the final failure, but keeps the status of
+// the final failure.
+type retriedError struct {
+	err    error
+	status *status.Status
+}
+
+func (e *retriedError) Error() string              { return e.err.Error() }
+func (e *retriedError) Unwrap() error              { return e.err }
+func (e *retriedError) GRPCStatus() *status.Status { return e.status }
@@ -0,0 +1,207 @@
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	errors "github.com/weathersource/go-errors"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	insecure "google.golang.org/grpc/credentials/insecure"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	metadata "google.golang.org/grpc/metadata"
+	status "google.golang.org/grpc/status"
+	bufconn "google.golang.org/grpc/test/bufconn"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+const checkMethod = "/grpc.health.v1.Health/Check"
+
+// flakyServer is a health server failing with its errors in turn before
+// succeeding.
+type flakyServer struct {
+	health.UnimplementedHealthServer
+
+	mu       sync.Mutex
+	errs     []error
+	trailers []metadata.MD
+	calls    int
+}
+
+func (s *flakyServer) Check(ctx context.Context, req *health.HealthCheckRequest) (*health.HealthCheckResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= len(s.trailers) && s.trailers[s.calls-1] != nil {
+		grpc.SetTrailer(ctx, s.trailers[s.calls-1])
+	}
+	if s.calls <= len(s.errs) {
+		return nil, s.errs[s.calls-1]
+	}
+	return &health.HealthCheckResponse{Status: health.HealthCheckResponse_SERVING}, nil
+}
+
+// dial serves s over an in-memory connection and returns a client using
+// interceptor.
+func dial(t *testing.T, s *flakyServer, interceptor grpc.UnaryClientInterceptor) health.HealthClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	health.RegisterHealthServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return health.NewHealthClient(conn)
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	s := &flakyServer{errs: []error{
+		status.Error(codes.Unavailable, "foo"),
+		errors.NewUnknownError("bar"),
+	}}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+
+	resp, err := client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, health.HealthCheckResponse_SERVING, resp.GetStatus())
+	assert.Equal(t, 3, s.calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.waits)
+}
+
+func TestUnaryClientInterceptorGiveUp(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	unavailable := status.Error(codes.Unavailable, "foo")
+	s := &flakyServer{errs: []error{unavailable, unavailable, unavailable, unavailable}}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+
+	_, err := client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Equal(t, 4, s.calls)
+	var final *errors.UnavailableError
+	if assert.True(t, stderrors.As(err, &final)) {
+		assert.Contains(t, final.GetMessage(), checkMethod+" failed")
+		attempts := final.GetCause().(*errors.Errors).Snapshot()
+		if assert.Equal(t, 4, len(attempts)) {
+			for _, attempt := range attempts {
+				assert.Equal(t, codes.Unavailable, status.Code(attempt))
+				assert.Equal(t, "foo", status.Convert(attempt).Message())
+			}
+		}
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, "foo", status.Convert(err).Message())
+}
+
+func TestUnaryClientInterceptorFinalStatus(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	info := &errdetails.ErrorInfo{Reason: "STATION_OFFLINE", Domain: "weathersource.com"}
+	st, _ := status.New(codes.Unavailable, "station offline").WithDetails(info)
+	s := &flakyServer{errs: []error{status.Error(codes.Unavailable, "foo"), st.Err(), st.Err(), st.Err()}}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+
+	_, err := client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Equal(t, 4, s.calls)
+	assert.IsType(t, &errors.UnavailableError{}, stderrors.Unwrap(err))
+	final := status.Convert(err)
+	assert.Equal(t, codes.Unavailable, final.Code())
+	assert.Equal(t, "station offline", final.Message())
+	if details := final.Details(); assert.Equal(t, 1, len(details)) {
+		assert.Equal(t, "STATION_OFFLINE", details[0].(*errdetails.ErrorInfo).GetReason())
+	}
+}
+
+func TestUnaryClientInterceptorNotRetryable(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	violation := &errdetails.BadRequest_FieldViolation{Field: "service", Description: "unknown service"}
+	st, _ := status.New(codes.InvalidArgument, "foo").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{violation},
+	})
+	s := &flakyServer{errs: []error{st.Err()}}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+
+	// the error of a call made once is returned unchanged
+	_, err := client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Equal(t, 1, s.calls)
+	final := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, final.Code())
+	assert.Equal(t, "foo", final.Message())
+	if details := final.Details(); assert.Equal(t, 1, len(details)) {
+		assert.Equal(t, "service", details[0].(*errdetails.BadRequest).GetFieldViolations()[0].GetField())
+	}
+}
+
+func TestUnaryClientInterceptorNotIdempotent(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	s := &flakyServer{errs: []error{status.Error(codes.Unavailable, "foo")}}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock)))
+
+	_, err := client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Equal(t, 1, s.calls)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, "foo", status.Convert(err).Message())
+}
+
+func TestUnaryClientInterceptorRetryInfo(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	st, _ := status.New(codes.ResourceExhausted, "foo").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	s := &flakyServer{errs: []error{st.Err()}}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+
+	_, err := client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.calls)
+	assert.Equal(t, []time.Duration{time.Second}, clock.waits)
+}
+
+func TestUnaryClientInterceptorPushback(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "foo")
+
+	clock := &fakeClock{now: time.Now()}
+	s := &flakyServer{
+		errs:     []error{unavailable},
+		trailers: []metadata.MD{metadata.Pairs(pushbackKey, "2000")},
+	}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+	_, err := client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{2 * time.Second}, clock.waits)
+
+	// a negative pushback stops retries
+	clock = &fakeClock{now: time.Now()}
+	s = &flakyServer{
+		errs:     []error{unavailable},
+		trailers: []metadata.MD{metadata.Pairs(pushbackKey, "-1")},
+	}
+	client = dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+	_, err = client.Check(context.Background(), &health.HealthCheckRequest{})
+	assert.Equal(t, 1, s.calls)
+	assert.Equal(t, unavailable.Error(), err.Error())
+}
+
+func TestUnaryClientInterceptorDeadline(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	s := &flakyServer{errs: []error{status.Error(codes.Unavailable, "foo")}}
+	client := dial(t, s, UnaryClientInterceptor(testPolicy(clock), checkMethod))
+
+	// the first retry would start after the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Check(ctx, &health.HealthCheckRequest{})
+	assert.Equal(t, 1, s.calls)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, "foo", status.Convert(err).Message())
+}
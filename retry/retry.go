@@ -0,0 +1,192 @@
+// Package retry retries operations failing with errors that the server
+// reports as recoverable, following the retry semantics documented by
+// github.com/weathersource/go-errors.
+//
+//	err := retry.Do(ctx, retry.DefaultPolicy, func(ctx context.Context) error {
+//		return client.GetStation(ctx, id)
+//	})
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand"
+	"time"
+
+	errors "github.com/weathersource/go-errors"
+)
+
+// Clock tells the time and waits for Do. It may be replaced in a Policy to
+// control the passage of time in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the Clock used when a Policy does not set one.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Policy determines when and how often Do retries an operation.
+type Policy struct {
+	// MaxAttempts is the maximum number of times the operation is called.
+	// Zero means no limit.
+	MaxAttempts int
+
+	// MaxElapsed is the maximum time from the first call of the operation to
+	// the start of the last retry. Zero means no limit.
+	MaxElapsed time.Duration
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries, unless the server asks
+	// for a longer delay. Zero means no limit.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the delay grows after each retry.
+	// Values below 1 are treated as 1.
+	Multiplier float64
+
+	// Jitter is the fraction, from 0 to 1, by which each delay is randomly
+	// lengthened or shortened, to spread out retries from many clients.
+	Jitter float64
+
+	// NonIdempotent indicates the operation may not be repeated without
+	// changing the result of having performed it once, so it is only retried
+	// after errors showing it was not performed (see errors.RetryOptions).
+	// Operations are assumed to be idempotent by default.
+	NonIdempotent bool
+
+	// Transactional indicates that the operation is a whole read-modify-write
+	// sequence, so it may be retried after an ABORTED error. Otherwise ABORTED
+	// errors are not retried, and should be retried by the caller at a higher
+	// level.
+	Transactional bool
+
+	// Clock is the clock used to measure and wait. Nil means the system clock.
+	Clock Clock
+}
+
+// DefaultPolicy makes up to 4 attempts of an idempotent operation within 30
+// seconds, doubling the delay between attempts from 100ms with 20% jitter.
+var DefaultPolicy = Policy{
+	MaxAttempts:    4,
+	MaxElapsed:     30 * time.Second,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// Do calls fn until it succeeds, returns an error that should not be retried,
+// or p gives up. Whether an error is retried is decided by errors.Retryable;
+// a retry delay requested by the server is waited for if longer than the
+// backoff. Errors calling for the whole transaction to be retried, such as
+// ABORTED errors, are retried only if p is Transactional. Errors wrapped with
+// Stop are never retried.
+//
+// Do gives up when the next retry would exceed p.MaxAttempts or p.MaxElapsed,
+// or start after the deadline of ctx. It returns nil if fn succeeded, and
+// otherwise an *errors.Errors holding the error of every attempt in order,
+// followed by the error of ctx if it was done while waiting to retry.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	clock := p.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	start := clock.Now()
+	attempts := errors.NewErrors()
+	backoff := p.InitialBackoff
+
+	for n := 1; ; n++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		var stop *stopError
+		if stderrors.As(err, &stop) {
+			attempts.Append(stop.err)
+			return attempts
+		}
+		attempts.Append(err)
+
+		retry, hint := p.retryable(err)
+		if !retry || (p.MaxAttempts > 0 && n >= p.MaxAttempts) {
+			return attempts
+		}
+		delay := p.jitter(backoff)
+		if hint > delay {
+			delay = hint
+		}
+		next := clock.Now().Add(delay)
+		if p.MaxElapsed > 0 && next.Sub(start) > p.MaxElapsed {
+			return attempts
+		}
+		if deadline, ok := ctx.Deadline(); ok && next.After(deadline) {
+			return attempts
+		}
+
+		select {
+		case <-ctx.Done():
+			attempts.Append(errors.FromContext(ctx))
+			return attempts
+		case <-clock.After(delay):
+		}
+		backoff = p.grow(backoff)
+	}
+}
+
+// Stop wraps err so that Do returns without retrying, as though err was not
+// retryable. Do records err itself, rather than the wrapper, among the errors
+// of its attempts.
+func Stop(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stopError{err: err}
+}
+
+// stopError is an error returned by Stop.
+type stopError struct{ err error }
+
+func (e *stopError) Error() string { return e.err.Error() }
+func (e *stopError) Unwrap() error { return e.err }
+
+// retryable reports whether p retries after err, and the delay requested by
+// the server, if any.
+func (p Policy) retryable(err error) (bool, time.Duration) {
+	d := errors.Retryable(err, errors.RetryOptions{Idempotent: !p.NonIdempotent})
+	switch d.Action {
+	case errors.RetryCall, errors.RetryAfterDelay:
+		return true, d.Delay
+	case errors.RetryTransaction:
+		return p.Transactional, d.Delay
+	}
+	return false, 0
+}
+
+// jitter returns d randomly lengthened or shortened by up to p.Jitter.
+func (p Policy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+	j := p.Jitter
+	if j > 1 {
+		j = 1
+	}
+	return time.Duration(float64(d) * (1 + j*(2*rand.Float64()-1)))
+}
+
+// grow returns the backoff following d.
+func (p Policy) grow(d time.Duration) time.Duration {
+	if p.Multiplier > 1 {
+		d = time.Duration(float64(d) * p.Multiplier)
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
@@ -0,0 +1,200 @@
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	errors "github.com/weathersource/go-errors"
+	codes "google.golang.org/grpc/codes"
+)
+
+// fakeClock is a Clock whose time advances only when waited on.
+type fakeClock struct {
+	now    time.Time
+	waits  []time.Duration
+	frozen bool
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	if !c.frozen {
+		c.now = c.now.Add(d)
+		ch <- c.now
+	}
+	return ch
+}
+
+// failing returns an operation failing with errs in turn, then succeeding, and
+// a pointer to the number of calls made.
+func failing(errs ...error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= len(errs) {
+			return errs[calls-1]
+		}
+		return nil
+	}, &calls
+}
+
+func testPolicy(clock Clock) Policy {
+	return Policy{
+		MaxAttempts:    4,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Multiplier:     2,
+		Clock:          clock,
+	}
+}
+
+func TestDo(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	fn, calls := failing(errors.NewUnavailableError("foo"), errors.NewUnknownError("bar"))
+
+	err := Do(context.Background(), testPolicy(clock), fn)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.waits)
+}
+
+func TestDoMaxAttempts(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	unavailable := errors.NewUnavailableError("foo")
+	fn, calls := failing(unavailable, unavailable, unavailable, unavailable, unavailable)
+
+	err := Do(context.Background(), testPolicy(clock), fn)
+	assert.Equal(t, 4, *calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, clock.waits)
+	attempts, ok := err.(*errors.Errors)
+	if assert.True(t, ok) {
+		assert.Equal(t, 4, attempts.Len())
+		assert.Equal(t, 4, attempts.Filter(errors.IsCode(codes.Unavailable)).Len())
+	}
+}
+
+func TestDoNotRetryable(t *testing.T) {
+	tests := []error{
+		errors.NewNotFoundError("foo"),
+		errors.NewInternalError("foo"),
+		errors.NewAbortedError("foo"),
+		stderrors.New("foo"),
+	}
+	for _, test := range tests {
+		clock := &fakeClock{now: time.Now()}
+		fn, calls := failing(test)
+
+		err := Do(context.Background(), testPolicy(clock), fn)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, 0, len(clock.waits))
+		assert.Equal(t, []error{test}, err.(*errors.Errors).Snapshot())
+	}
+}
+
+func TestDoNotIdempotent(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	fn, calls := failing(errors.NewUnavailableError("foo"))
+
+	p := testPolicy(clock)
+	p.NonIdempotent = true
+	err := Do(context.Background(), p, fn)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, 1, err.(*errors.Errors).Len())
+
+	// the request was rejected, so it may be retried
+	fn, calls = failing(errors.NewResourceExhaustedError("foo"))
+	assert.Nil(t, Do(context.Background(), p, fn))
+	assert.Equal(t, 2, *calls)
+}
+
+func TestDoTransactional(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	fn, calls := failing(errors.NewAbortedError("foo"))
+
+	p := testPolicy(clock)
+	p.Transactional = true
+	assert.Nil(t, Do(context.Background(), p, fn))
+	assert.Equal(t, 2, *calls)
+}
+
+func TestDoRetryDelay(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	exhausted := errors.NewResourceExhaustedError("foo")
+	exhausted.SetRetryDelay(time.Second)
+	unavailable := errors.NewUnavailableError("foo")
+	unavailable.SetRetryDelay(time.Millisecond)
+	fn, calls := failing(exhausted, unavailable)
+
+	assert.Nil(t, Do(context.Background(), testPolicy(clock), fn))
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{time.Second, 200 * time.Millisecond}, clock.waits)
+}
+
+func TestDoMaxElapsed(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	unavailable := errors.NewUnavailableError("foo")
+	fn, calls := failing(unavailable, unavailable, unavailable)
+
+	p := testPolicy(clock)
+	p.MaxElapsed = 250 * time.Millisecond
+	err := Do(context.Background(), p, fn)
+	assert.Equal(t, 2, *calls)
+	assert.Equal(t, 2, err.(*errors.Errors).Len())
+}
+
+func TestDoContext(t *testing.T) {
+	unavailable := errors.NewUnavailableError("foo")
+
+	// the context is canceled while waiting
+	clock := &fakeClock{now: time.Now(), frozen: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, testPolicy(clock), func(context.Context) error {
+		calls++
+		cancel()
+		return unavailable
+	})
+	assert.Equal(t, 1, calls)
+	attempts := err.(*errors.Errors).Snapshot()
+	if assert.Equal(t, 2, len(attempts)) {
+		assert.Equal(t, unavailable, attempts[0])
+		assert.IsType(t, &errors.CanceledError{}, attempts[1])
+	}
+
+	// the next retry would start after the deadline
+	clock = &fakeClock{now: time.Now()}
+	ctx, cancel = context.WithDeadline(context.Background(), clock.now.Add(50*time.Millisecond))
+	defer cancel()
+	fn, fnCalls := failing(unavailable)
+	err = Do(ctx, testPolicy(clock), fn)
+	assert.Equal(t, 1, *fnCalls)
+	assert.Equal(t, 1, err.(*errors.Errors).Len())
+}
+
+func TestPolicyJitter(t *testing.T) {
+	p := Policy{Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		d := p.jitter(time.Second)
+		assert.True(t, d >= 500*time.Millisecond && d <= 1500*time.Millisecond, d)
+	}
+	assert.Equal(t, time.Second, Policy{}.jitter(time.Second))
+}
+
+func TestStop(t *testing.T) {
+	assert.Nil(t, Stop(nil))
+
+	clock := &fakeClock{now: time.Now()}
+	unavailable := errors.NewUnavailableError("foo")
+	fn, calls := failing(Stop(unavailable))
+
+	err := Do(context.Background(), testPolicy(clock), fn)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, []error{unavailable}, err.(*errors.Errors).Snapshot())
+	assert.Equal(t, unavailable.Error(), Stop(unavailable).Error())
+	assert.True(t, stderrors.Is(Stop(unavailable), unavailable))
+}
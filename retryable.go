@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"time"
+
+	codes "google.golang.org/grpc/codes"
+)
+
+// RetryAction is the action a client should take after an operation failed.
+type RetryAction int
+
+// Retry actions returned by Retryable
+const (
+	// NoRetry indicates the operation should not be retried.
+	NoRetry RetryAction = iota
+
+	// RetryCall indicates the failing call may be retried with a backoff.
+	RetryCall
+
+	// RetryTransaction indicates the failing call should not be retried on its
+	// own, but the higher-level operation it is part of, such as a
+	// read-modify-write sequence, may be restarted.
+	RetryTransaction
+
+	// RetryAfterDelay indicates the failing call may be retried once the delay
+	// requested by the server has passed.
+	RetryAfterDelay
+)
+
+// String returns the name of a.
+func (a RetryAction) String() string {
+	switch a {
+	case NoRetry:
+		return "NO_RETRY"
+	case RetryCall:
+		return "RETRY_CALL"
+	case RetryTransaction:
+		return "RETRY_TRANSACTION"
+	case RetryAfterDelay:
+		return "RETRY_AFTER_DELAY"
+	}
+	return "UNKNOWN"
+}
+
+// RetryDecision is the result of Retryable.
+type RetryDecision struct {
+	// Action is the action the client should take.
+	Action RetryAction
+
+	// Delay is the delay requested by the server (see RetryDelay), or zero if
+	// it requested none. It is always set for RetryAfterDelay, and may be set
+	// for RetryTransaction.
+	Delay time.Duration
+}
+
+// RetryOptions describe the failed operation to Retryable.
+type RetryOptions struct {
+	// Idempotent indicates the operation may be repeated without changing the
+	// result of having performed it once, so it may be retried after errors
+	// that leave it unknown whether the operation took effect.
+	Idempotent bool
+}
+
+// Retryable decides whether and how an operation that failed with err should
+// be retried, replacing the ill-defined notion of Temporary. The decision is
+// made by the kind of err, or, if no classifier has an opinion about err, by
+// the kind of the first error it wraps that one has an opinion about:
+//
+//	UNAVAILABLE, UNKNOWN, DEADLINE_EXCEEDED  RetryCall, if opts.Idempotent
+//	RESOURCE_EXHAUSTED                       RetryCall
+//	ABORTED                                  RetryTransaction
+//	other                                    NoRetry
+//
+// Every RetryCall decision becomes a RetryAfterDelay decision if the server
+// requested a retry delay.
+func Retryable(err error, opts RetryOptions) RetryDecision {
+	if err == nil {
+		return RetryDecision{Action: NoRetry}
+	}
+	delay, hinted := RetryDelay(err)
+
+	var action RetryAction
+	switch Kind(err) {
+	case codes.Unavailable, codes.Unknown, codes.DeadlineExceeded:
+		if opts.Idempotent {
+			action = RetryCall
+		}
+	case codes.ResourceExhausted:
+		action = RetryCall
+	case codes.Aborted:
+		return RetryDecision{Action: RetryTransaction, Delay: delay}
+	}
+	if action == RetryCall && hinted {
+		return RetryDecision{Action: RetryAfterDelay, Delay: delay}
+	}
+	return RetryDecision{Action: action}
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+func TestRetryable(t *testing.T) {
+	exhausted := NewResourceExhaustedError("foo")
+	exhausted.SetRetryDelay(time.Second)
+	unavailable := NewUnavailableError("foo")
+	unavailable.SetRetryDelay(2 * time.Second)
+	aborted := NewAbortedError("foo")
+	aborted.SetRetryDelay(3 * time.Second)
+
+	tests := []struct {
+		err        error
+		idempotent bool
+		decision   RetryDecision
+	}{
+		{nil, true, RetryDecision{Action: NoRetry}},
+		{NewUnavailableError("foo"), true, RetryDecision{Action: RetryCall}},
+		{NewUnavailableError("foo"), false, RetryDecision{Action: NoRetry}},
+		{unavailable, true, RetryDecision{Action: RetryAfterDelay, Delay: 2 * time.Second}},
+		{unavailable, false, RetryDecision{Action: NoRetry}},
+		{NewUnknownError("foo"), true, RetryDecision{Action: RetryCall}},
+		{NewDeadlineExceededError("foo"), true, RetryDecision{Action: RetryCall}},
+		{NewDeadlineExceededError("foo"), false, RetryDecision{Action: NoRetry}},
+		{NewResourceExhaustedError("foo"), false, RetryDecision{Action: RetryCall}},
+		{exhausted, false, RetryDecision{Action: RetryAfterDelay, Delay: time.Second}},
+		{NewAbortedError("foo"), false, RetryDecision{Action: RetryTransaction}},
+		{aborted, true, RetryDecision{Action: RetryTransaction, Delay: 3 * time.Second}},
+		{NewInternalError("foo"), true, RetryDecision{Action: NoRetry}},
+		{NewNotFoundError("foo"), true, RetryDecision{Action: NoRetry}},
+		{NewCanceledError("foo"), true, RetryDecision{Action: NoRetry}},
+		{errors.New("foo"), true, RetryDecision{Action: NoRetry}},
+
+		// wrapped causes
+		{fmt.Errorf("calling: %w", NewUnavailableError("foo")), true, RetryDecision{Action: RetryCall}},
+		{fmt.Errorf("calling: %w", exhausted), true, RetryDecision{Action: RetryAfterDelay, Delay: time.Second}},
+		{fmt.Errorf("calling: %w", status.Error(codes.Aborted, "foo")), true, RetryDecision{Action: RetryTransaction}},
+		{fmt.Errorf("calling: %w", context.DeadlineExceeded), true, RetryDecision{Action: RetryCall}},
+		{fmt.Errorf("calling: %w", timeoutError{timeout: true}), true, RetryDecision{Action: RetryCall}},
+		{NewErrors(NewUnavailableError("foo"), NewUnavailableError("bar")), true, RetryDecision{Action: RetryCall}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.decision, Retryable(test.err, RetryOptions{Idempotent: test.idempotent}), fmt.Sprint(test.err))
+	}
+}
+
+func TestRetryActionString(t *testing.T) {
+	assert.Equal(t, "NO_RETRY", NoRetry.String())
+	assert.Equal(t, "RETRY_CALL", RetryCall.String())
+	assert.Equal(t, "RETRY_TRANSACTION", RetryTransaction.String())
+	assert.Equal(t, "RETRY_AFTER_DELAY", RetryAfterDelay.String())
+	assert.Equal(t, "UNKNOWN", RetryAction(-1).String())
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+	status "google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryDelay returns how long the server that returned err asked the client to
+// wait before retrying, if it did. The delay is taken from the first of:
+//
+//   - an error implementing GetRetryDelay() time.Duration, such as
+//     UnavailableError, that reports a positive delay
+//   - a RetryInfo detail of the error's gRPC status
+//   - the Retry-After header of an upstream HTTP response (see HTTPClassifier)
+func RetryDelay(err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	var delayErr interface{ GetRetryDelay() time.Duration }
+	if errors.As(err, &delayErr) {
+		if d := delayErr.GetRetryDelay(); d > 0 {
+			return d, true
+		}
+	}
+	if s, ok := status.FromError(err); ok {
+		for _, d := range s.Details() {
+			if info, ok := d.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+				return info.GetRetryDelay().AsDuration(), true
+			}
+		}
+	}
+	var respErr interface{ Response() *http.Response }
+	if errors.As(err, &respErr) {
+		if resp := respErr.Response(); resp != nil {
+			return retryAfter(resp.Header.Get("Retry-After"))
+		}
+	}
+	return 0, false
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// withRetryDelay returns s with a RetryInfo detail holding d, or s itself if d
+// is not positive.
+func withRetryDelay(s *status.Status, d time.Duration) *status.Status {
+	if d <= 0 {
+		return s
+	}
+	ds, err := s.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	if err != nil {
+		return s
+	}
+	return ds
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRetryDelay(t *testing.T) {
+	unavailable := NewUnavailableError("foo")
+	unavailable.SetRetryDelay(time.Second)
+
+	s, _ := status.New(codes.ResourceExhausted, "foo").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)})
+
+	header := http.Header{}
+	header.Set("Retry-After", "3")
+	dateHeader := http.Header{}
+	dateHeader.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	badHeader := http.Header{}
+	badHeader.Set("Retry-After", "soon")
+
+	tests := []struct {
+		err   error
+		delay time.Duration
+		ok    bool
+	}{
+		{nil, 0, false},
+		{errors.New("foo"), 0, false},
+		{NewUnavailableError("foo"), 0, false},
+		{unavailable, time.Second, true},
+		{fmt.Errorf("calling: %w", unavailable), time.Second, true},
+		{s.Err(), 2 * time.Second, true},
+		{fmt.Errorf("calling: %w", s.Err()), 2 * time.Second, true},
+		{responseError{&http.Response{Status: "429 Too Many Requests", StatusCode: 429, Header: header}}, 3 * time.Second, true},
+		{responseError{&http.Response{Status: "503 Service Unavailable", StatusCode: 503, Header: dateHeader}}, 0, true},
+		{responseError{&http.Response{Status: "503 Service Unavailable", StatusCode: 503, Header: badHeader}}, 0, false},
+	}
+	for _, test := range tests {
+		delay, ok := RetryDelay(test.err)
+		assert.Equal(t, test.delay, delay, fmt.Sprint(test.err))
+		assert.Equal(t, test.ok, ok, fmt.Sprint(test.err))
+	}
+}
+
+func TestPassthroughRetryDelay(t *testing.T) {
+	s, _ := status.New(codes.Unavailable, "foo").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+
+	res := NewPassthroughError("foo", s.Err())
+	unavailable, ok := res.(*UnavailableError)
+	if assert.True(t, ok) {
+		assert.Equal(t, time.Second, unavailable.GetRetryDelay())
+	}
+
+	res = NewPassthroughError("foo", status.Error(codes.Unavailable, "foo"))
+	assert.Equal(t, time.Duration(0), res.(*UnavailableError).GetRetryDelay())
+}
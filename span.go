@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SpanStatusCode is the status of a span, with the values of the OpenTelemetry
+// codes.Code.
+type SpanStatusCode uint32
+
+// Span status codes
+const (
+	SpanStatusUnset SpanStatusCode = 0
+	SpanStatusError SpanStatusCode = 1
+	SpanStatusOK    SpanStatusCode = 2
+)
+
+// SpanAttribute is a span or event attribute. Its value is a string or an
+// int64.
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is the subset of the OpenTelemetry trace.Span method set used by
+// RecordOnSpan, with the OpenTelemetry types replaced by those of this
+// package, so that this package does not depend on the OpenTelemetry API. A
+// trace.Span does not satisfy Span itself, and must be passed to RecordOnSpan
+// through an adapter such as:
+//
+//	import (
+//		errors "github.com/weathersource/go-errors"
+//		"go.opentelemetry.io/otel/attribute"
+//		"go.opentelemetry.io/otel/codes"
+//		"go.opentelemetry.io/otel/trace"
+//	)
+//
+//	// otelSpan adapts a trace.Span to errors.Span.
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetStatus(c errors.SpanStatusCode, description string) {
+//		s.Span.SetStatus(codes.Code(c), description)
+//	}
+//
+//	func (s otelSpan) SetAttributes(attrs ...errors.SpanAttribute) {
+//		s.Span.SetAttributes(otelAttributes(attrs)...)
+//	}
+//
+//	func (s otelSpan) AddEvent(name string, attrs ...errors.SpanAttribute) {
+//		s.Span.AddEvent(name, trace.WithAttributes(otelAttributes(attrs)...))
+//	}
+//
+//	// otelAttributes converts attrs to OpenTelemetry attributes.
+//	func otelAttributes(attrs []errors.SpanAttribute) []attribute.KeyValue {
+//		kvs := make([]attribute.KeyValue, 0, len(attrs))
+//		for _, a := range attrs {
+//			switch v := a.Value.(type) {
+//			case string:
+//				kvs = append(kvs, attribute.String(a.Key, v))
+//			case int64:
+//				kvs = append(kvs, attribute.Int64(a.Key, v))
+//			}
+//		}
+//		return kvs
+//	}
+//
+// which is used as
+//
+//	errors.RecordOnSpan(otelSpan{trace.SpanFromContext(ctx)}, err)
+type Span interface {
+	IsRecording() bool
+	SetStatus(code SpanStatusCode, description string)
+	SetAttributes(attrs ...SpanAttribute)
+	AddEvent(name string, attrs ...SpanAttribute)
+}
+
+// OpenTelemetry semantic convention keys set by RecordOnSpan
+const (
+	spanExceptionEvent      = "exception"
+	spanExceptionType       = "exception.type"
+	spanExceptionMessage    = "exception.message"
+	spanExceptionStacktrace = "exception.stacktrace"
+	spanGRPCStatusCode      = "rpc.grpc.status_code"
+	spanHTTPStatusCode      = "http.response.status_code"
+)
+
+// RecordOnSpan records err on span following the OpenTelemetry semantic
+// conventions. It sets the span status to error, sets the
+// rpc.grpc.status_code and http.response.status_code attributes of the span to
+// the codes of err, and adds an exception event with the exception.type,
+// exception.message and, for errors from this package, exception.stacktrace
+// attributes. Nothing is recorded if err is nil or span is not recording.
+func RecordOnSpan(span Span, err error) {
+	if err == nil || span == nil || !span.IsRecording() {
+		return
+	}
+	c := Kind(err)
+	httpStatus := httpCode(c)
+	var codeErr interface{ GetCode() int }
+	if errors.As(err, &codeErr) {
+		httpStatus = codeErr.GetCode()
+	}
+
+	span.SetStatus(SpanStatusError, err.Error())
+	span.SetAttributes(
+		SpanAttribute{Key: spanGRPCStatusCode, Value: int64(c)},
+		SpanAttribute{Key: spanHTTPStatusCode, Value: int64(httpStatus)},
+	)
+
+	attrs := []SpanAttribute{
+		{Key: spanExceptionType, Value: typeName(err)},
+		{Key: spanExceptionMessage, Value: err.Error()},
+	}
+	var stackErr interface{ GetStack() stack }
+	if errors.As(err, &stackErr) {
+		if s := stackErr.GetStack(); len(s) > 0 {
+			attrs = append(attrs, SpanAttribute{
+				Key:   spanExceptionStacktrace,
+				Value: strings.TrimPrefix(s.String(), "\n"),
+			})
+		}
+	}
+	span.AddEvent(spanExceptionEvent, attrs...)
+}
+
+// typeName returns the name of the type of err, as recorded in exception.type
+// by the OpenTelemetry SDK.
+func typeName(err error) string {
+	t := reflect.TypeOf(err)
+	if t.PkgPath() == "" && t.Name() == "" {
+		return t.String()
+	}
+	return fmt.Sprintf("%s.%s", t.PkgPath(), t.Name())
+}
@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+type spanEvent struct {
+	name  string
+	attrs map[string]interface{}
+}
+
+type fakeSpan struct {
+	recording   bool
+	status      SpanStatusCode
+	description string
+	attrs       map[string]interface{}
+	events      []spanEvent
+}
+
+func newFakeSpan() *fakeSpan {
+	return &fakeSpan{recording: true, attrs: make(map[string]interface{})}
+}
+
+func (s *fakeSpan) IsRecording() bool { return s.recording }
+
+func (s *fakeSpan) SetStatus(code SpanStatusCode, description string) {
+	s.status, s.description = code, description
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...SpanAttribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *fakeSpan) AddEvent(name string, attrs ...SpanAttribute) {
+	e := spanEvent{name: name, attrs: make(map[string]interface{})}
+	for _, a := range attrs {
+		e.attrs[a.Key] = a.Value
+	}
+	s.events = append(s.events, e)
+}
+
+func TestRecordOnSpan(t *testing.T) {
+	span := newFakeSpan()
+	err := NewNotFoundError("foo")
+	RecordOnSpan(span, err)
+
+	assert.Equal(t, SpanStatusError, span.status)
+	assert.Equal(t, err.Error(), span.description)
+	assert.Equal(t, map[string]interface{}{
+		"rpc.grpc.status_code":      int64(5),
+		"http.response.status_code": int64(404),
+	}, span.attrs)
+	if assert.Equal(t, 1, len(span.events)) {
+		event := span.events[0]
+		assert.Equal(t, "exception", event.name)
+		assert.Equal(t, "*errors.NotFoundError", event.attrs["exception.type"])
+		assert.Equal(t, err.Error(), event.attrs["exception.message"])
+		stacktrace := event.attrs["exception.stacktrace"].(string)
+		assert.True(t, strings.HasPrefix(stacktrace, err.GetStack()[0].file), stacktrace)
+	}
+}
+
+func TestRecordOnSpanForeignErrors(t *testing.T) {
+	tests := []struct {
+		err        error
+		errType    string
+		grpcStatus int64
+		httpStatus int64
+	}{
+		{errors.New("foo"), "*errors.errorString", int64(codes.Internal), 500},
+		{fmt.Errorf("calling: %w", NewUnavailableError("foo")), "*fmt.wrapError", int64(codes.Unavailable), 503},
+		{status.Error(codes.PermissionDenied, "foo"), "*status.Error", int64(codes.PermissionDenied), 403},
+		{timeoutError{timeout: true}, "github.com/weathersource/go-errors.timeoutError", int64(codes.DeadlineExceeded), 504},
+	}
+	for _, test := range tests {
+		span := newFakeSpan()
+		RecordOnSpan(span, test.err)
+		assert.Equal(t, SpanStatusError, span.status)
+		assert.Equal(t, test.grpcStatus, span.attrs["rpc.grpc.status_code"], test.err.Error())
+		assert.Equal(t, test.httpStatus, span.attrs["http.response.status_code"], test.err.Error())
+		if assert.Equal(t, 1, len(span.events)) {
+			assert.Equal(t, test.errType, span.events[0].attrs["exception.type"])
+			_, ok := span.events[0].attrs["exception.stacktrace"]
+			assert.Equal(t, test.errType == "*fmt.wrapError", ok)
+		}
+	}
+}
+
+func TestRecordOnSpanNothing(t *testing.T) {
+	span := newFakeSpan()
+	RecordOnSpan(span, nil)
+	assert.Equal(t, SpanStatusUnset, span.status)
+	assert.Equal(t, 0, len(span.events))
+
+	span.recording = false
+	RecordOnSpan(span, NewInternalError("foo"))
+	assert.Equal(t, SpanStatusUnset, span.status)
+	assert.Equal(t, 0, len(span.events))
+
+	RecordOnSpan(nil, NewInternalError("foo"))
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	codes "google.golang.org/grpc/codes"
+)
+
+// SQLClassifier classifies database/sql errors:
+//
+//	sql.ErrNoRows      NOT_FOUND
+//	sql.ErrTxDone      FAILED_PRECONDITION
+//	sql.ErrConnDone    UNAVAILABLE
+//	driver.ErrBadConn  UNAVAILABLE
+//
+// and driver errors exposing SQLState() string, such as those of
+// github.com/jackc/pgx and github.com/lib/pq, by their SQLSTATE (see
+// SQLStateCode).
+//
+// The SQLSTATE of driver errors is recorded in the metadata of errors returned
+// by NewPassthroughError.
+var SQLClassifier Classifier = ClassifierFunc(classifySQL)
+
+// SQLStateCode maps a SQLSTATE error code onto the gRPC code identifying the
+// correlating type from this package, or codes.OK if it has no mapping:
+//
+//	23505  unique_violation        ALREADY_EXISTS
+//	23503  foreign_key_violation   FAILED_PRECONDITION
+//	23514  check_violation         FAILED_PRECONDITION
+//	40001  serialization_failure   ABORTED
+//	40P01  deadlock_detected       ABORTED
+//	57014  query_canceled          CANCELED
+//	53xxx  insufficient resources  RESOURCE_EXHAUSTED
+//	08xxx  connection exception    UNAVAILABLE
+func SQLStateCode(state string) codes.Code {
+	switch strings.ToUpper(state) {
+	case "23505":
+		return codes.AlreadyExists
+	case "23503", "23514":
+		return codes.FailedPrecondition
+	case "40001", "40P01":
+		return codes.Aborted
+	case "57014":
+		return codes.Canceled
+	}
+	switch {
+	case strings.HasPrefix(state, "53"):
+		return codes.ResourceExhausted
+	case strings.HasPrefix(state, "08"):
+		return codes.Unavailable
+	}
+	return codes.OK
+}
+
+// classifySQL implements SQLClassifier.
+func classifySQL(err error) codes.Code {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return codes.NotFound
+	case errors.Is(err, sql.ErrTxDone):
+		return codes.FailedPrecondition
+	case errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, driver.ErrBadConn):
+		return codes.Unavailable
+	}
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		return SQLStateCode(stateErr.SQLState())
+	}
+	return codes.OK
+}
+
+// describeSQL records the SQLSTATE of driver errors in info.
+func describeSQL(err error, info *errorInfo) {
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		info.metadata["sqlstate"] = stateErr.SQLState()
+	}
+}
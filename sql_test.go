@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+)
+
+type sqlStateError struct{ state string }
+
+func (e sqlStateError) Error() string    { return "ERROR: " + e.state }
+func (e sqlStateError) SQLState() string { return e.state }
+
+func TestSQLStateCode(t *testing.T) {
+	tests := []struct {
+		state string
+		code  codes.Code
+	}{
+		{"23505", codes.AlreadyExists},
+		{"23503", codes.FailedPrecondition},
+		{"23514", codes.FailedPrecondition},
+		{"40001", codes.Aborted},
+		{"40P01", codes.Aborted},
+		{"40p01", codes.Aborted},
+		{"57014", codes.Canceled},
+		{"53100", codes.ResourceExhausted},
+		{"53300", codes.ResourceExhausted},
+		{"08006", codes.Unavailable},
+		{"08001", codes.Unavailable},
+		{"42601", codes.OK},
+		{"", codes.OK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, SQLStateCode(test.state), test.state)
+	}
+}
+
+func TestSQLClassifier(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{sql.ErrNoRows, codes.NotFound},
+		{fmt.Errorf("station KDEN: %w", sql.ErrNoRows), codes.NotFound},
+		{sql.ErrTxDone, codes.FailedPrecondition},
+		{sql.ErrConnDone, codes.Unavailable},
+		{driver.ErrBadConn, codes.Unavailable},
+		{sqlStateError{"23505"}, codes.AlreadyExists},
+		{fmt.Errorf("insert: %w", sqlStateError{"40001"}), codes.Aborted},
+		{sqlStateError{"42601"}, codes.OK},
+		{errors.New("foo"), codes.OK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, SQLClassifier.Classify(test.err))
+	}
+}
+
+func TestPassthroughSQLMetadata(t *testing.T) {
+	res := ClientPassthroughPolicy.NewPassthroughError("foo", fmt.Errorf("insert: %w", sqlStateError{"23505"}))
+	alreadyExists, ok := res.(*AlreadyExistsError)
+	if assert.True(t, ok) {
+		assert.Equal(t, map[string]string{"sqlstate": "23505"}, alreadyExists.GetMetadata())
+	}
+
+	res = ClientPassthroughPolicy.NewPassthroughError("foo", sql.ErrNoRows)
+	assert.IsType(t, &NotFoundError{}, res)
+	assert.Nil(t, res.(*NotFoundError).GetMetadata())
+
+	// by default, client errors are masked
+	res = NewPassthroughError("foo", sql.ErrNoRows)
+	assert.IsType(t, &InternalError{}, res)
+}
@@ -0,0 +1,224 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// SetMaxLen limits the number of errors retained by e to n. Errors appended
+// once e holds n errors are not retained, but are counted by Len, Omitted and
+// Summary, considered when choosing the code of e, and reported by Error as
+// "and N more". If e already holds more than n errors, the excess errors at
+// the end are omitted. A value of n <= 0 removes the limit.
+func (e *Errors) SetMaxLen(n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	e.maxLen = n
+	if n > 0 && len(e.errs) > n {
+		for _, err := range e.errs[n:] {
+			e.forget(err)
+			e.omit(err)
+		}
+		e.errs = e.errs[:n:n]
+	}
+}
+
+// SetDedup sets whether e discards errors having the same code and message as
+// an error it already holds. Discarded errors are counted by Omitted and
+// Summary. Enabling deduplication discards any duplicates e already holds.
+func (e *Errors) SetDedup(dedup bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dedup = dedup
+	e.seen = nil
+	if !dedup {
+		return
+	}
+	errs := e.errs
+	e.errs = make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err == nil {
+			// retain nils passed to NewErrors so that indexes are preserved
+			e.errs = append(e.errs, err)
+			continue
+		}
+		e.retain(err)
+	}
+}
+
+// Omitted returns the number of errors appended to e that were not retained
+// because of the limits set by SetMaxLen or SetDedup.
+func (e *Errors) Omitted() int {
+	_, omitted := e.state()
+	return countOmitted(omitted)
+}
+
+// Summary returns a summary of e grouping its errors by code, most frequent
+// first, such as "312 × INVALID ARGUMENT, 4 × NOT FOUND". Omitted errors are
+// included in the summary.
+func (e *Errors) Summary() string {
+	if e == nil {
+		return ""
+	}
+	e.mu.RLock()
+	counts := make(map[codes.Code]int, len(e.omitted))
+	for c, n := range e.omitted {
+		counts[c] += n
+	}
+	errs := append([]error(nil), e.errs...)
+	e.mu.RUnlock()
+
+	for _, err := range errs {
+		if err != nil {
+			counts[status.Code(err)]++
+		}
+	}
+
+	cs := make([]codes.Code, 0, len(counts))
+	for c := range counts {
+		cs = append(cs, c)
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if counts[cs[i]] != counts[cs[j]] {
+			return counts[cs[i]] > counts[cs[j]]
+		}
+		return codeLabel(cs[i]) < codeLabel(cs[j])
+	})
+	parts := make([]string, len(cs))
+	for i, c := range cs {
+		parts[i] = fmt.Sprintf("%d × %s", counts[c], codeLabel(c))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Filter returns a new Errors holding the errors in e for which pred returns
+// true, in order.
+func (e *Errors) Filter(pred func(error) bool) *Errors {
+	matched, _ := e.Partition(pred)
+	return matched
+}
+
+// Partition returns two new Errors, the first holding the errors in e for
+// which pred returns true, and the second holding the rest, both in order.
+func (e *Errors) Partition(pred func(error) bool) (*Errors, *Errors) {
+	matched, rest := NewErrors(), NewErrors()
+	for _, err := range e.Snapshot() {
+		if err == nil {
+			continue
+		}
+		if pred(err) {
+			matched.errs = append(matched.errs, err)
+		} else {
+			rest.errs = append(rest.errs, err)
+		}
+	}
+	return matched, rest
+}
+
+// IsCode returns a predicate, for use with Filter and Partition, reporting
+// whether the gRPC code of an error is one of cs.
+func IsCode(cs ...codes.Code) func(error) bool {
+	return func(err error) bool {
+		c := status.Code(err)
+		for _, want := range cs {
+			if c == want {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// retain adds err to e, unless e is full or err duplicates an error already in
+// e, in which case err is omitted. e must be locked for writing.
+func (e *Errors) retain(err error) {
+	if e.dedup {
+		key := dedupKey(err)
+		if _, ok := e.seen[key]; ok {
+			e.omit(err)
+			return
+		}
+		if e.maxLen <= 0 || len(e.errs) < e.maxLen {
+			if e.seen == nil {
+				e.seen = make(map[string]struct{})
+			}
+			e.seen[key] = struct{}{}
+		}
+	}
+	if e.maxLen > 0 && len(e.errs) >= e.maxLen {
+		e.omit(err)
+		return
+	}
+	e.errs = append(e.errs, err)
+}
+
+// forget removes err from the errors seen for deduplication after it is
+// removed from e. e must be locked for writing.
+func (e *Errors) forget(err error) {
+	if e.dedup && err != nil {
+		delete(e.seen, dedupKey(err))
+	}
+}
+
+// omit counts err as omitted from e. e must be locked for writing.
+func (e *Errors) omit(err error) {
+	if e.omitted == nil {
+		e.omitted = make(map[codes.Code]int)
+	}
+	e.omitted[status.Code(err)]++
+}
+
+// dedupKey returns the key identifying duplicates of err.
+func dedupKey(err error) string {
+	msg := err.Error()
+	if wxErr, ok := err.(interface{ GetMessage() string }); ok {
+		msg = wxErr.GetMessage()
+	}
+	return fmt.Sprintf("%d:%s", status.Code(err), msg)
+}
+
+// codeLabel returns the label used in error messages for a gRPC code.
+func codeLabel(c codes.Code) string {
+	switch c {
+	case codes.OK:
+		return "OK"
+	case codes.Aborted:
+		return "ABORTED"
+	case codes.AlreadyExists:
+		return "ALREADY EXISTS"
+	case codes.Canceled:
+		return "CANCELED"
+	case codes.DataLoss:
+		return "DATA LOSS"
+	case codes.DeadlineExceeded:
+		return "DEADLINE EXCEEDED"
+	case codes.FailedPrecondition:
+		return "FAILED PRECONDITION"
+	case codes.Internal:
+		return "INTERNAL ERROR"
+	case codes.InvalidArgument:
+		return "INVALID ARGUMENT"
+	case codes.NotFound:
+		return "NOT FOUND"
+	case codes.OutOfRange:
+		return "OUT OF RANGE"
+	case codes.PermissionDenied:
+		return "PERMISSION DENIED"
+	case codes.ResourceExhausted:
+		return "RESOURCE EXHAUSTED"
+	case codes.Unauthenticated:
+		return "UNAUTHENTICATED"
+	case codes.Unavailable:
+		return "UNAVAILABLE"
+	case codes.Unimplemented:
+		return "NOT IMPLEMENTED"
+	}
+	return "UNKNOWN ERROR"
+}
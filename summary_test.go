@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	codes "google.golang.org/grpc/codes"
+)
+
+func TestErrorsSetMaxLen(t *testing.T) {
+	SetVerbosity(Info)
+
+	errs := NewErrors()
+	errs.SetMaxLen(2)
+	for i := 0; i < 5; i++ {
+		errs.Append(NewInvalidArgumentError("foo"))
+	}
+	assert.Equal(t, 2, len(errs.Snapshot()))
+	assert.Equal(t, 3, errs.Omitted())
+	assert.Equal(t, "MULTIPLE ERRORS.\n#1: error 400: INVALID ARGUMENT. foo\n#2: error 400: INVALID ARGUMENT. foo\nand 3 more", errs.Error())
+	assert.Equal(t, "5 × INVALID ARGUMENT", errs.Summary())
+
+	// existing errors beyond the limit are omitted
+	errs = NewErrors(errors.New("foo"), errors.New("bar"), errors.New("baz"))
+	errs.SetMaxLen(1)
+	assert.Equal(t, 1, len(errs.Snapshot()))
+	assert.Equal(t, 2, errs.Omitted())
+	assert.Equal(t, "MULTIPLE ERRORS.\n#1: foo\nand 2 more", errs.Error())
+	assert.Equal(t, "MULTIPLE ERRORS.\n#1: foo\nand 2 more", errs.GRPCStatus().Message())
+
+	// popping makes room again
+	errs.Pop()
+	errs.Append(errors.New("bat"))
+	assert.Equal(t, "bat", errs.Snapshot()[0].Error())
+
+	// removing the limit
+	errs.SetMaxLen(0)
+	errs.Append(errors.New("foo"))
+	assert.Equal(t, 2, len(errs.Snapshot()))
+}
+
+func TestErrorsSetDedup(t *testing.T) {
+	errs := NewErrors(NewNotFoundError("foo"), NewNotFoundError("foo"), NewNotFoundError("bar"))
+	errs.SetDedup(true)
+	assert.Equal(t, 2, len(errs.Snapshot()))
+	assert.Equal(t, 1, errs.Omitted())
+
+	errs.Append(NewNotFoundError("bar"), NewInvalidArgumentError("foo"), errors.New("foo"), errors.New("foo"))
+	assert.Equal(t, 4, len(errs.Snapshot()))
+	assert.Equal(t, 3, errs.Omitted())
+	assert.Equal(t, "4 × NOT FOUND, 2 × UNKNOWN ERROR, 1 × INVALID ARGUMENT", errs.Summary())
+
+	// removed errors may be appended again
+	errs.Pop()
+	errs.Append(errors.New("foo"))
+	assert.Equal(t, 4, len(errs.Snapshot()))
+
+	errs.SetDedup(false)
+	errs.Append(errors.New("foo"))
+	assert.Equal(t, 5, len(errs.Snapshot()))
+
+	// dedup with a limit
+	errs = NewErrors()
+	errs.SetDedup(true)
+	errs.SetMaxLen(1)
+	errs.Append(errors.New("foo"), errors.New("bar"), errors.New("bar"))
+	assert.Equal(t, 1, len(errs.Snapshot()))
+	assert.Equal(t, 2, errs.Omitted())
+}
+
+func TestErrorsOmittedOnly(t *testing.T) {
+	SetVerbosity(Info)
+
+	errs := NewErrors()
+	errs.SetMaxLen(1)
+	errs.Append(NewInvalidArgumentError("foo"))
+	for i := 0; i < 5; i++ {
+		errs.Append(NewUnavailableError("foo"))
+	}
+	assert.Equal(t, 6, errs.Len())
+
+	// omitted errors count towards the code
+	assert.Equal(t, 503, errs.GetCode())
+	assert.Equal(t, codes.Unavailable, errs.GRPCStatus().Code())
+
+	// and still do once the retained errors are removed
+	errs.Pop()
+	assert.Equal(t, "MULTIPLE ERRORS.\nand 5 more", errs.Error())
+	assert.Equal(t, 5, errs.Len())
+	assert.Equal(t, "MULTIPLE ERRORS.", errs.GetMessage())
+	assert.Equal(t, 503, errs.GetCode())
+	if s := errs.GRPCStatus(); assert.NotNil(t, s) {
+		assert.Equal(t, codes.Unavailable, s.Code())
+		assert.Equal(t, errs.Error(), s.Message())
+	}
+}
+
+func TestErrorsDrainOmitted(t *testing.T) {
+	for _, remove := range []func(*Errors) error{(*Errors).Pop, (*Errors).Shift} {
+		errs := NewErrors()
+		errs.SetMaxLen(2)
+		errs.SetDedup(true)
+		errs.Append(NewNotFoundError("foo"), NewNotFoundError("foo"), NewNotFoundError("bar"), NewNotFoundError("baz"))
+		assert.Equal(t, 4, errs.Len())
+
+		removed := 0
+		for errs.Len() > 0 {
+			if remove(errs) != nil {
+				removed++
+			}
+		}
+		assert.Equal(t, 2, removed)
+		assert.Equal(t, 0, errs.Omitted())
+		assert.Equal(t, "", errs.Error())
+		assert.Equal(t, 200, errs.GetCode())
+		assert.Nil(t, errs.GRPCStatus())
+	}
+}
+
+func TestErrorsMergeOmitted(t *testing.T) {
+	in := NewErrors()
+	in.SetMaxLen(1)
+	in.Append(NewNotFoundError("foo"), NewNotFoundError("bar"), NewUnavailableError("foo"))
+
+	errs := NewErrors(NewInvalidArgumentError("foo"))
+	errs.Merge(in)
+	assert.Equal(t, 2, len(errs.Snapshot()))
+	assert.Equal(t, 2, errs.Omitted())
+	assert.Equal(t, 4, errs.Len())
+	assert.Equal(t, "2 × NOT FOUND, 1 × INVALID ARGUMENT, 1 × UNAVAILABLE", errs.Summary())
+	assert.Equal(t, codes.Unavailable, errs.GRPCStatus().Code())
+}
+
+func TestErrorsSummary(t *testing.T) {
+	var nilErrs *Errors
+	assert.Equal(t, "", nilErrs.Summary())
+	assert.Equal(t, "", NewErrors().Summary())
+
+	errs := NewErrors()
+	for i := 0; i < 312; i++ {
+		errs.Append(NewInvalidArgumentError("foo"))
+	}
+	for i := 0; i < 4; i++ {
+		errs.Append(NewNotFoundError("foo"))
+	}
+	errs.Append(NewAbortedError("foo"), NewAlreadyExistsError("foo"))
+	assert.Equal(t, "312 × INVALID ARGUMENT, 4 × NOT FOUND, 1 × ABORTED, 1 × ALREADY EXISTS", errs.Summary())
+}
+
+func TestErrorsFilter(t *testing.T) {
+	errs := NewErrors(
+		NewInvalidArgumentError("foo"),
+		NewNotFoundError("bar"),
+		nil,
+		NewUnavailableError("baz"),
+		NewInvalidArgumentError("bat"),
+	)
+
+	invalid := errs.Filter(IsCode(codes.InvalidArgument))
+	assert.Equal(t, 2, invalid.Len())
+	assert.Equal(t, "INVALID ARGUMENT. bat", invalid.Snapshot()[1].(*InvalidArgumentError).GetMessage())
+
+	temporary, permanent := errs.Partition(func(err error) bool {
+		return err.(interface{ Temporary() bool }).Temporary()
+	})
+	assert.Equal(t, 1, temporary.Len())
+	assert.Equal(t, 3, permanent.Len())
+
+	client := errs.Filter(IsCode(codes.InvalidArgument, codes.NotFound))
+	assert.Equal(t, 3, client.Len())
+
+	// e is left unchanged
+	assert.Equal(t, 5, errs.Len())
+}
@@ -113,6 +113,24 @@ func TestUnauthenticatedErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestUnauthenticatedErrorGetReason(t *testing.T) {
+	for _, test := range UnauthenticatedErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewUnauthenticatedError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestUnauthenticatedErrorGetMetadata(t *testing.T) {
+	for _, test := range UnauthenticatedErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewUnauthenticatedError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestUnauthenticatedErrorJson(t *testing.T) {
 	for _, test := range UnauthenticatedErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"time"
+
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 )
@@ -39,6 +41,9 @@ type UnavailableError struct {
 	logMessage string
 	cause      error
 	stack      stack
+	reason     string
+	metadata   map[string]string
+	retryDelay time.Duration
 	rpcCode    codes.Code
 }
 
@@ -48,7 +53,7 @@ func NewUnavailableError(Message string, cause ...error) *UnavailableError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &UnavailableError{
+	e := &UnavailableError{
 		Code:       503,
 		Message:    "UNAVAILABLE. Unable to handle the request due to a temporary overloading or maintenance.",
 		logMessage: Message,
@@ -56,6 +61,8 @@ func NewUnavailableError(Message string, cause ...error) *UnavailableError {
 		stack:      getTrace(),
 		rpcCode:    codes.Unavailable,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -65,6 +72,9 @@ func (e *UnavailableError) Error() string { return errorStr(e) }
 func (e *UnavailableError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *UnavailableError) Temporary() bool { return true }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -79,7 +89,27 @@ func (e *UnavailableError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *UnavailableError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *UnavailableError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *UnavailableError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *UnavailableError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *UnavailableError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
+// GetRetryDelay returns how long the client should wait before retrying, or
+// zero if no delay was set.
+func (e *UnavailableError) GetRetryDelay() time.Duration { return e.retryDelay }
+
+// SetRetryDelay sets how long the client should wait before retrying. The
+// delay is sent to gRPC clients in a RetryInfo detail.
+func (e *UnavailableError) SetRetryDelay(d time.Duration) { e.retryDelay = d }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *UnavailableError) GRPCStatus() *status.Status {
-	return status.New(e.rpcCode, e.Message)
+	return withRetryDelay(status.New(e.rpcCode, e.Message), e.retryDelay)
 }
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	assert "github.com/stretchr/testify/assert"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
 	codes "google.golang.org/grpc/codes"
 )
 
@@ -113,6 +115,40 @@ func TestUnavailableErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestUnavailableErrorGetReason(t *testing.T) {
+	for _, test := range UnavailableErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewUnavailableError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestUnavailableErrorGetMetadata(t *testing.T) {
+	for _, test := range UnavailableErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewUnavailableError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
+func TestUnavailableErrorRetryDelay(t *testing.T) {
+	for _, test := range UnavailableErrorTests {
+		assert.Equal(t, time.Duration(0), test.err.GetRetryDelay())
+		assert.Equal(t, 0, len(test.err.GRPCStatus().Details()))
+	}
+
+	err := NewUnavailableError("Message")
+	err.SetRetryDelay(5 * time.Second)
+	assert.Equal(t, 5*time.Second, err.GetRetryDelay())
+
+	details := err.GRPCStatus().Details()
+	if assert.Equal(t, 1, len(details)) {
+		assert.Equal(t, 5*time.Second, details[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+	}
+}
+
 func TestUnavailableErrorJson(t *testing.T) {
 	for _, test := range UnavailableErrorTests {
 		json, _ := json.Marshal(test.err)
@@ -35,6 +35,8 @@ type UnknownError struct {
 	logMessage string
 	cause      error
 	stack      stack
+	reason     string
+	metadata   map[string]string
 	rpcCode    codes.Code
 }
 
@@ -44,13 +46,15 @@ func NewUnknownError(Message string, cause ...error) *UnknownError {
 	if len(cause) > 0 {
 		c = NewErrors(cause...)
 	}
-	return &UnknownError{
+	e := &UnknownError{
 		Code:    500,
 		Message: "UNKNOWN ERROR. " + Message,
 		cause:   c,
 		stack:   getTrace(),
 		rpcCode: codes.Unknown,
 	}
+	created(e)
+	return e
 }
 
 // Error implements the error interface
@@ -60,6 +64,9 @@ func (e *UnknownError) Error() string { return errorStr(e) }
 func (e *UnknownError) Timeout() bool { return false }
 
 // Temporary indicates if this error is potentially recoverable.
+//
+// Deprecated: Temporary does not consider the idempotency of the failed
+// operation or retry delays requested by the server. Use Retryable instead.
 func (e *UnknownError) Temporary() bool { return true }
 
 // GetCode returns the HTTP status code associated with this error.
@@ -74,6 +81,18 @@ func (e *UnknownError) GetCause() error { return e.cause }
 // GetStack returns the trace stack associated with this error.
 func (e *UnknownError) GetStack() stack { return e.stack }
 
+// GetReason returns the machine-readable reason associated with this error.
+func (e *UnknownError) GetReason() string { return e.reason }
+
+// setReason sets the machine-readable reason associated with this error.
+func (e *UnknownError) setReason(reason string) { e.reason = reason }
+
+// GetMetadata returns the structured metadata associated with this error.
+func (e *UnknownError) GetMetadata() map[string]string { return e.metadata }
+
+// setMetadata sets the structured metadata associated with this error.
+func (e *UnknownError) setMetadata(metadata map[string]string) { e.metadata = metadata }
+
 // GRPCStatus implements an interface required to return proper GRPC status codes
 func (e *UnknownError) GRPCStatus() *status.Status {
 	return status.New(e.rpcCode, e.Message)
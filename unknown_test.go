@@ -113,6 +113,24 @@ func TestUnknownErrorGetStack(t *testing.T) {
 	}
 }
 
+func TestUnknownErrorGetReason(t *testing.T) {
+	for _, test := range UnknownErrorTests {
+		assert.Equal(t, "", test.err.GetReason())
+	}
+	err := NewUnknownError("Message")
+	err.setReason("FOO")
+	assert.Equal(t, "FOO", err.GetReason())
+}
+
+func TestUnknownErrorGetMetadata(t *testing.T) {
+	for _, test := range UnknownErrorTests {
+		assert.Nil(t, test.err.GetMetadata())
+	}
+	err := NewUnknownError("Message")
+	err.setMetadata(map[string]string{"foo": "bar"})
+	assert.Equal(t, map[string]string{"foo": "bar"}, err.GetMetadata())
+}
+
 func TestUnknownErrorJson(t *testing.T) {
 	for _, test := range UnknownErrorTests {
 		json, _ := json.Marshal(test.err)